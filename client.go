@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/equinix/rest-go/internal/api"
 	"github.com/go-resty/resty/v2"
@@ -15,15 +16,39 @@ import (
 const (
 	//LogLevelEnvVar is OS variable name that controlls logging level
 	LogLevelEnvVar = "EQUINIX_REST_LOG"
+	//Version is a version of Equinix REST client module
+	Version = "1.4.0"
 )
 
 //Client describes Equinix REST client implementation.
 //Implementation is based on github.com/go-resty
 type Client struct {
 	//PageSize determines default page size for GET requests on resource collections
-	PageSize int
-	baseURL  string
-	ctx      context.Context
+	PageSize             int
+	baseURL              string
+	ctx                  context.Context
+	optimisticLocking    bool
+	etags                *etagStore
+	idempotencyKeyHeader string
+	operationConfig      *OperationConfig
+	breaker              *circuitBreaker
+	rateLimiter          *rateLimiter
+	quota                *quotaTracker
+	flights              *flightGroup
+	environment          Environment
+	dryRun               *dryRunPlan
+	auditSink            AuditSink
+	transport            http.RoundTripper
+	transportChain       http.RoundTripper
+	cache                CacheStorage
+	cacheIdentity        func(req *http.Request) string
+	har                  *harRecorder
+	harMaxEntries        int
+	correlationIDHeader  string
+	userAgentProducts    []string
+	userAgentPlatform    bool
+	deprecations         *deprecationTracker
+	apiVersions          []apiVersion
 	*resty.Client
 }
 
@@ -35,6 +60,12 @@ type Error struct {
 	Message string
 	//ApplicationErrors is list of one or more application sub-errors
 	ApplicationErrors []ApplicationError
+	//IdempotencyKey is idempotency key that was sent with failed request
+	IdempotencyKey string
+	//CorrelationID is correlation ID returned by the server or, when server
+	//did not return one, correlation ID that was sent with failed request
+	CorrelationID string
+	cause         error
 }
 
 //ApplicationError describes standardized application error
@@ -64,21 +95,38 @@ func (e Error) Error() string {
 	return errorStr
 }
 
+//Is reports whether error matches given target error, like ErrPreconditionFailed
+func (e Error) Is(target error) bool {
+	return target == ErrPreconditionFailed && e.HTTPCode == http.StatusPreconditionFailed
+}
+
+//Unwrap returns underlying cause of an error, like transport error or ErrCircuitOpen
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
 func (e ApplicationError) Error() string {
 	return fmt.Sprintf("Code: %q, Property: %q, Message: %q, AdditionalInfo: %q", e.Code, e.Property, e.Message, e.AdditionalInfo)
 }
 
 //NewClient creates new Equinix REST client with a given HTTP context, URL and http client.
+//Given http client is copied and is not modified by Equinix REST client.
 //Equinix REST client is based on github.com/go-resty
 func NewClient(ctx context.Context, baseURL string, httpClient *http.Client) *Client {
-	resty := resty.NewWithClient(httpClient)
+	clientCopy := *httpClient
+	resty := resty.NewWithClient(&clientCopy)
 	resty.SetHeader("Accept", "application/json")
 	resty.SetDebug(isDebugEnabled(osEnvProvider{}))
-	return &Client{
-		100,
-		baseURL,
-		ctx,
-		resty}
+	c := &Client{
+		PageSize:            100,
+		baseURL:             baseURL,
+		ctx:                 ctx,
+		etags:               newETagStore(),
+		quota:               newQuotaTracker(),
+		harMaxEntries:       DefaultHARMaxEntries,
+		correlationIDHeader: DefaultCorrelationIDHeader,
+		Client:              resty}
+	return c.applyUserAgent()
 }
 
 //SetPageSize sets  page size used by Equinix REST client for paginated queries
@@ -93,30 +141,119 @@ func (c *Client) Execute(req *resty.Request, method string, path string) error {
 	return err
 }
 
-//Do runs given method on a given path with given request and returns response and error
+//Do runs given method on a given path with given request and returns response and error.
+//Path is joined with client's base URL and is expected to be escaped, use ExpandPath
+//to build path from a template
 func (c *Client) Do(method string, path string, req *resty.Request) (*resty.Response, error) {
-	if path[0:1] == "/" {
-		path = path[1:]
+	return c.do(c.ctx, method, path, req)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+//buildTransport chains transport middlewares enabled on the client on top of
+//client's base transport. Transport of underlying http client that was not built by
+//the client, i.e. set with SetTransport, becomes the new base transport.
+//Responses served from cache do not reach HAR recorder
+func (c *Client) buildTransport() {
+	if c.transportChain == nil || c.GetClient().Transport != c.transportChain {
+		c.transport = c.GetClient().Transport
 	}
-	url := c.baseURL + "/" + path
-	resp, err := req.SetContext(c.ctx).Execute(method, url)
+	transport := c.transport
+	if c.har != nil {
+		transport = &harTransport{recorder: c.har, next: transport}
+	}
+	if c.cache != nil {
+		transport = &cachingTransport{storage: c.cache, identity: c.cacheIdentity, next: transport, now: time.Now}
+	}
+	//chain is kept only when it was built by the client, as comparing
+	//transports of other, possibly uncomparable, types may panic
+	c.transportChain = nil
+	if c.har != nil || c.cache != nil {
+		c.transportChain = transport
+	}
+	c.SetTransport(transport)
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, req *resty.Request) (*resty.Response, error) {
+	url, err := joinURL(c.baseURL, path)
 	if err != nil {
-		restErr := Error{Message: "HTTP operation failed: " + err.Error()}
+		return nil, Error{Message: "invalid request URL: " + err.Error()}
+	}
+	url, version, err := c.versionURL(url, req)
+	if err != nil {
+		return nil, err
+	}
+	ctx = c.correlationContext(ctx, req)
+	if c.dryRun != nil && isMutatingMethod(method) {
+		return c.planRequest(method, url, req)
+	}
+	event := AuditEvent{Time: time.Now(), Method: method, Path: path, Actor: auditActor(ctx, req)}
+	var resp *resty.Response
+	if c.flights != nil && method == resty.MethodGet {
+		resp, err = c.executeShared(ctx, url, req)
+	} else {
+		resp, err = c.execute(ctx, method, url, req)
+	}
+	err = version.check(resp, err)
+	c.audit(event, req, resp, err)
+	if err != nil || c.operationConfig == nil || resp.StatusCode() != http.StatusAccepted {
+		return resp, err
+	}
+	return c.followOperation(ctx, resp, req)
+}
+
+func (c *Client) execute(ctx context.Context, method string, url string, req *resty.Request) (*resty.Response, error) {
+	if err := c.guardEnvironment(ctx, method, url, req); err != nil {
+		return nil, err
+	}
+	correlationID := c.applyCorrelationID(ctx, req)
+	c.applyIfMatch(method, url, req)
+	idempotencyKey, err := c.applyIdempotencyKey(method, req)
+	if err != nil {
+		return nil, Error{Message: err.Error()}
+	}
+	if c.rateLimiter != nil {
+		if err := c.rateLimiter.wait(ctx, method, url); err != nil {
+			return nil, Error{Message: "waiting for rate limiter failed: " + err.Error(), cause: err}
+		}
+	}
+	if err := c.quota.wait(ctx); err != nil {
+		return nil, Error{Message: "waiting for rate limit quota failed: " + err.Error(), cause: err}
+	}
+	var breakerKey string
+	if c.breaker != nil {
+		breakerKey = c.breaker.key(method, url)
+		if err := c.breaker.allow(breakerKey); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := req.SetContext(ctx).Execute(method, url)
+	c.quota.capture(resp)
+	if c.deprecations != nil {
+		c.deprecations.check(method, url, resp)
+	}
+	if c.breaker != nil {
+		c.breaker.record(ctx, breakerKey, resp, err)
+	}
+	if err != nil {
+		restErr := Error{Message: "HTTP operation failed: " + err.Error(), IdempotencyKey: idempotencyKey, CorrelationID: correlationID, cause: err}
 		if resp != nil {
 			restErr.HTTPCode = resp.StatusCode()
 		}
 		return resp, restErr
 	}
 	if resp.IsError() {
-		return resp, createError(resp)
+		restErr := createError(resp)
+		restErr.IdempotencyKey = idempotencyKey
+		restErr.CorrelationID = c.CorrelationID(resp)
+		return resp, restErr
 	}
+	c.captureETag(method, url, resp)
 	return resp, nil
 }
 
-//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
-// Unexported package methods
-//_______________________________________________________________________
-
 func mapErrorBodyAPIToDomain(body []byte) ([]ApplicationError, bool) {
 	apiError := api.ErrorResponse{}
 	if err := json.Unmarshal(body, &apiError); err == nil {
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/equinix/rest-go/internal/redact"
+	"github.com/go-resty/resty/v2"
+)
+
+//AuditEvent describes mutating request made by the client
+type AuditEvent struct {
+	//Time is a time when request was made
+	Time time.Time `json:"time"`
+	//Method is HTTP method of a request
+	Method string `json:"method"`
+	//Path is request path, as given to the client
+	Path string `json:"path"`
+	//Body is request body with secrets redacted
+	Body string `json:"body,omitempty"`
+	//StatusCode is HTTP status code of a response, zero when no response was received
+	StatusCode int `json:"statusCode"`
+	//ErrorCodes are codes of application errors returned by the server
+	ErrorCodes []string `json:"errorCodes,omitempty"`
+	//Error is a message of an error returned by the client, empty on success
+	Error string `json:"error,omitempty"`
+	//CorrelationID is correlation identifier of a request
+	CorrelationID string `json:"correlationId,omitempty"`
+	//Actor identifies on whose behalf request was made, as given with WithAuditActor or SetAuditActor
+	Actor string `json:"actor,omitempty"`
+	//Environment is client's environment label
+	Environment string `json:"environment,omitempty"`
+}
+
+//AuditSink receives audit events
+type AuditSink interface {
+	//Record receives given audit event. It is called synchronously after each
+	//request other than GET, HEAD and OPTIONS
+	Record(event AuditEvent)
+}
+
+//SetAuditSink sets sink that receives audit event for each request other than
+//GET, HEAD and OPTIONS. Nil sink disables auditing
+func (c *Client) SetAuditSink(sink AuditSink) *Client {
+	c.auditSink = sink
+	return c
+}
+
+//WithAuditActor returns context derived from a given one that carries actor
+//recorded in audit events. It is meant for functions accepting context, like ExecuteBatch
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+//SetAuditActor sets actor recorded in audit event of a given request
+func SetAuditActor(req *resty.Request, actor string) *resty.Request {
+	return req.SetContext(WithAuditActor(req.Context(), actor))
+}
+
+//JSONLinesAuditSink is AuditSink that writes each event as a JSON object in a separate line
+type JSONLinesAuditSink struct {
+	mu      sync.Mutex
+	w       io.Writer
+	encoder *json.Encoder
+	err     error
+}
+
+//NewJSONLinesAuditSink creates new JSON lines audit sink that writes to a given writer
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w, encoder: json.NewEncoder(w)}
+}
+
+//OpenAuditLog opens JSON lines audit sink that appends to a file with a given path.
+//File is created when it does not exist
+func OpenAuditLog(path string) (*JSONLinesAuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLinesAuditSink(f), nil
+}
+
+//Record writes given event. Write errors are available with Err
+func (s *JSONLinesAuditSink) Record(event AuditEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.encoder.Encode(event); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+//Err returns first error that occurred while writing events
+func (s *JSONLinesAuditSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+//Close closes underlying writer when it is io.Closer
+func (s *JSONLinesAuditSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if closer, ok := s.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type auditActorKey struct{}
+
+func auditActor(ctx context.Context, req *resty.Request) string {
+	if actor, ok := req.Context().Value(auditActorKey{}).(string); ok {
+		return actor
+	}
+	actor, _ := ctx.Value(auditActorKey{}).(string)
+	return actor
+}
+
+func (c *Client) audit(event AuditEvent, req *resty.Request, resp *resty.Response, err error) {
+	if c.auditSink == nil {
+		return
+	}
+	switch event.Method {
+	case resty.MethodGet, resty.MethodHead, resty.MethodOptions:
+		return
+	}
+	event.Environment = c.environment.Name
+	if body, bodyErr := requestBody(req); bodyErr == nil {
+		event.Body = string(redact.Body(body, redact.DefaultFields))
+	}
+	if resp != nil && resp.RawResponse != nil {
+		event.StatusCode = resp.StatusCode()
+		event.CorrelationID = c.CorrelationID(resp)
+	} else if c.correlationIDHeader != "" {
+		event.CorrelationID = req.Header.Get(c.correlationIDHeader)
+	}
+	if err != nil {
+		event.Error = err.Error()
+		restErr := Error{}
+		if errors.As(err, &restErr) {
+			for _, appErr := range restErr.ApplicationErrors {
+				event.ErrorCodes = append(event.ErrorCodes, appErr.Code)
+			}
+		}
+	}
+	c.auditSink.Record(event)
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testAuditSink struct {
+	events []AuditEvent
+}
+
+func (s *testAuditSink) Record(event AuditEvent) {
+	s.events = append(s.events, event)
+}
+
+func TestAudit(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+"/devices",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusCreated, `{"uuid":"1"}`)
+			resp.Header.Set("X-Correlation-ID", "server-id")
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodDelete, baseURL+"/devices/2",
+		httpmock.NewStringResponder(http.StatusBadRequest, `[{"errorCode":"IC-LAYER2-4021","errorMessage":"invalid state"}]`))
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, "{}"))
+	sink := &testAuditSink{}
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetAuditSink(sink).SetEnvironment("sandbox", false)
+	body := map[string]string{"name": "device", "password": "secret"}
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, "/devices")
+	cli.Execute(SetAuditActor(cli.R().SetBody(body), "jdoe"), resty.MethodPost, "/devices")
+	cli.ExecuteBatch(WithAuditActor(context.Background(), "cleanup-job"),
+		[]BatchItem{{Method: resty.MethodDelete, Path: "/devices/2"}}, nil)
+	//then
+	if assert.Len(t, sink.events, 2, "Event should be recorded for each mutating request") {
+		created := sink.events[0]
+		assert.False(t, created.Time.IsZero(), "Event time should be set")
+		assert.Equal(t, resty.MethodPost, created.Method, "Method matches")
+		assert.Equal(t, "/devices", created.Path, "Path matches")
+		assert.Equal(t, `{"name":"device","password":"REDACTED"}`, created.Body, "Body should be redacted")
+		assert.Equal(t, http.StatusCreated, created.StatusCode, "Status code matches")
+		assert.Equal(t, "server-id", created.CorrelationID, "Correlation ID matches")
+		assert.Equal(t, "jdoe", created.Actor, "Actor matches")
+		assert.Equal(t, "sandbox", created.Environment, "Environment matches")
+		deleted := sink.events[1]
+		assert.Equal(t, http.StatusBadRequest, deleted.StatusCode, "Status code matches")
+		assert.Equal(t, []string{"IC-LAYER2-4021"}, deleted.ErrorCodes, "Error codes match")
+		assert.NotEmpty(t, deleted.Error, "Error message should be recorded")
+		assert.Equal(t, "cleanup-job", deleted.Actor, "Actor from context matches")
+	}
+}
+
+func TestJSONLinesAuditSink(t *testing.T) {
+	//given
+	buf := &bytes.Buffer{}
+	sink := NewJSONLinesAuditSink(buf)
+	//when
+	sink.Record(AuditEvent{Method: resty.MethodPost, Path: "/devices", StatusCode: http.StatusCreated})
+	sink.Record(AuditEvent{Method: resty.MethodDelete, Path: "/devices/1", StatusCode: http.StatusNoContent})
+	//then
+	assert.Nil(t, sink.Err(), "Error should not be returned")
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if assert.Len(t, lines, 2, "Each event should be written in a separate line") {
+		event := AuditEvent{}
+		assert.Nil(t, json.Unmarshal(lines[1], &event), "Line should be valid JSON")
+		assert.Equal(t, "/devices/1", event.Path, "Path matches")
+	}
+}
+
+func TestOpenAuditLog(t *testing.T) {
+	//given
+	dir, err := ioutil.TempDir("", "audit")
+	if err != nil {
+		assert.Failf(t, "cannot create temporary directory", "%s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "audit.log")
+	for i := 0; i < 2; i++ {
+		sink, err := OpenAuditLog(path)
+		if err != nil {
+			assert.Failf(t, "cannot open audit log", "%s", err)
+		}
+		//when
+		sink.Record(AuditEvent{Method: resty.MethodPost, Path: "/devices"})
+		assert.Nil(t, sink.Close(), "Audit log should be closed")
+	}
+	//then
+	data, _ := ioutil.ReadFile(path)
+	assert.Len(t, bytes.Split(bytes.TrimSpace(data), []byte("\n")), 2, "Events should be appended")
+}
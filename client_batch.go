@@ -0,0 +1,205 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//ErrBatchAborted is matched by errors.Is for Error of a batch item that was not
+//executed, or was interrupted, because batch was aborted
+var ErrBatchAborted = errors.New("batch aborted")
+
+//BatchMode describes how batch execution reacts to failed items
+type BatchMode int
+
+const (
+	//BatchBestEffort mode executes all batch items regardless of failures
+	BatchBestEffort BatchMode = iota
+	//BatchFailFast mode aborts batch execution after first failed item
+	BatchFailFast
+)
+
+//BatchConfig is used to describe how ExecuteBatch runs batch items
+type BatchConfig struct {
+	//Concurrency is a maximum number of batch items executed at the same time
+	Concurrency int
+	//Mode determines reaction to failed items
+	Mode BatchMode
+}
+
+//DefaultBatchConfig returns BatchConfig with default values
+func DefaultBatchConfig() *BatchConfig {
+	return &BatchConfig{
+		Concurrency: 5,
+		Mode:        BatchBestEffort,
+	}
+}
+
+//SetConcurrency sets maximum number of batch items executed at the same time
+func (c *BatchConfig) SetConcurrency(v int) *BatchConfig {
+	c.Concurrency = v
+	return c
+}
+
+//SetMode sets reaction to failed items
+func (c *BatchConfig) SetMode(v BatchMode) *BatchConfig {
+	c.Mode = v
+	return c
+}
+
+//BatchItem describes single request executed by ExecuteBatch
+type BatchItem struct {
+	//Method is HTTP method of a request
+	Method string
+	//Path is request path, joined with client's base URL
+	Path string
+	//Request is a request to execute. New request is used when nil
+	Request *resty.Request
+}
+
+//BatchResult describes outcome of a single batch item
+type BatchResult struct {
+	//Item is executed batch item
+	Item BatchItem
+	//Response is item's response, nil when item was not executed
+	Response *resty.Response
+	//Err is item's error
+	Err error
+}
+
+//BatchItemError describes error of a single failed batch item
+type BatchItemError struct {
+	//Index is a position of an item in the batch
+	Index int
+	//Method is HTTP method of an item
+	Method string
+	//Path is request path of an item
+	Path string
+	//Err is item's error, typically Error
+	Err error
+}
+
+func (e BatchItemError) Error() string {
+	return fmt.Sprintf("[%d] %s %s: %s", e.Index, e.Method, e.Path, e.Err)
+}
+
+//Unwrap returns cause of an error
+func (e BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+//BatchError describes unsuccessful outcome of ExecuteBatch operation
+type BatchError struct {
+	//Total is a number of items in the batch
+	Total int
+	//Failed lists errors of failed items
+	Failed []BatchItemError
+	//Aborted is a number of items that were not executed, or were interrupted, due to batch abort
+	Aborted int
+}
+
+func (e BatchError) Error() string {
+	msgs := make([]string, len(e.Failed))
+	for i := range e.Failed {
+		msgs[i] = e.Failed[i].Error()
+	}
+	return fmt.Sprintf("%d of %d batch requests failed, %d aborted: [%s]", len(e.Failed), e.Total, e.Aborted, strings.Join(msgs, "; "))
+}
+
+//ExecuteBatch executes given batch items with concurrency limit and returns per-item results,
+//in order of items. BatchError is returned when any item failed or was aborted.
+//In BatchFailFast mode, first failure cancels in-flight items and skips pending ones
+func (c *Client) ExecuteBatch(ctx context.Context, items []BatchItem, conf *BatchConfig) ([]BatchResult, error) {
+	if conf == nil {
+		conf = DefaultBatchConfig()
+	}
+	concurrency := conf.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(items) {
+		concurrency = len(items)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]BatchResult, len(items))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if ctx.Err() != nil {
+					results[i] = BatchResult{Item: items[i], Err: batchAbortedError(ctx.Err())}
+					continue
+				}
+				results[i] = c.executeBatchItem(ctx, items[i])
+				if results[i].Err != nil && conf.Mode == BatchFailFast {
+					cancel()
+				}
+			}
+		}()
+	}
+feed:
+	for i := range items {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			for j := i; j < len(items); j++ {
+				results[j] = BatchResult{Item: items[j], Err: batchAbortedError(ctx.Err())}
+			}
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	return results, newBatchError(results)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (c *Client) executeBatchItem(ctx context.Context, item BatchItem) BatchResult {
+	req := item.Request
+	if req == nil {
+		req = c.R()
+	}
+	resp, err := c.do(ctx, item.Method, item.Path, req)
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		err = batchAbortedError(ctx.Err())
+	}
+	return BatchResult{Item: item, Response: resp, Err: err}
+}
+
+func batchAbortedError(cause error) Error {
+	return Error{Message: fmt.Sprintf("batch request aborted: %s", cause), cause: ErrBatchAborted}
+}
+
+func newBatchError(results []BatchResult) error {
+	batchErr := BatchError{Total: len(results)}
+	for i, result := range results {
+		switch {
+		case result.Err == nil:
+		case errors.Is(result.Err, ErrBatchAborted):
+			batchErr.Aborted++
+		default:
+			batchErr.Failed = append(batchErr.Failed, BatchItemError{
+				Index:  i,
+				Method: result.Item.Method,
+				Path:   result.Item.Path,
+				Err:    result.Err,
+			})
+		}
+	}
+	if len(batchErr.Failed) == 0 && batchErr.Aborted == 0 {
+		return nil
+	}
+	return batchErr
+}
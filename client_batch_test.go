@@ -0,0 +1,96 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteBatchBestEffort(t *testing.T) {
+	//given
+	var mu sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if r.URL.Path == "/connections/3" || r.URL.Path == "/connections/5" {
+			return httpmock.NewStringResponse(http.StatusNotFound, `{"errorCode":"EQ-3000","errorMessage":"not found"}`), nil
+		}
+		return httpmock.NewStringResponse(http.StatusNoContent, ""), nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	items := make([]BatchItem, 8)
+	for i := range items {
+		items[i] = BatchItem{Method: resty.MethodDelete, Path: "/connections/" + string(rune('0'+i))}
+	}
+	//when
+	results, err := cli.ExecuteBatch(context.Background(), items, DefaultBatchConfig().SetConcurrency(3))
+	//then
+	assert.Equal(t, len(items), mock.GetTotalCallCount(), "All items should be executed")
+	assert.LessOrEqual(t, maxInFlight, 3, "Concurrency limit should be respected")
+	assert.Len(t, results, len(items), "Result for each item should be returned")
+	assert.Nil(t, results[0].Err, "Successful item should not have error")
+	assert.IsType(t, BatchError{}, err, "Error should be rest.BatchError type")
+	batchErr := err.(BatchError)
+	assert.Equal(t, 0, batchErr.Aborted, "No items should be aborted")
+	if assert.Len(t, batchErr.Failed, 2, "Failed items should be listed") {
+		assert.Equal(t, 3, batchErr.Failed[0].Index, "Failed item index matches")
+		assert.Equal(t, 5, batchErr.Failed[1].Index, "Failed item index matches")
+		restErr := Error{}
+		assert.True(t, errors.As(batchErr.Failed[0], &restErr), "Item error should be rest.Error")
+		assert.Equal(t, http.StatusNotFound, restErr.HTTPCode, "Item error HTTP code matches")
+		assert.Equal(t, "EQ-3000", restErr.ApplicationErrors[0].Code, "Item application error code matches")
+	}
+}
+
+func TestExecuteBatchFailFast(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	items := []BatchItem{
+		{Method: resty.MethodGet, Path: "/devices/1"},
+		{Method: resty.MethodGet, Path: "/devices/2"},
+		{Method: resty.MethodGet, Path: "/devices/3"},
+	}
+	//when
+	results, err := cli.ExecuteBatch(context.Background(), items, DefaultBatchConfig().SetConcurrency(1).SetMode(BatchFailFast))
+	//then
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Items after failure should not be executed")
+	assert.IsType(t, BatchError{}, err, "Error should be rest.BatchError type")
+	assert.Len(t, err.(BatchError).Failed, 1, "Failed item should be listed")
+	assert.Equal(t, 2, err.(BatchError).Aborted, "Remaining items should be aborted")
+	assert.True(t, errors.Is(results[2].Err, ErrBatchAborted), "Skipped item error should be classified as aborted")
+	assert.Nil(t, results[2].Response, "Skipped item should not have response")
+}
+
+func TestExecuteBatchSuccess(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]string{"key": "value"}))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	result := TestObject{}
+	items := []BatchItem{{Method: resty.MethodGet, Path: "/objects/1", Request: cli.R().SetResult(&result)}}
+	//when
+	results, err := cli.ExecuteBatch(context.Background(), items, nil)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, http.StatusOK, results[0].Response.StatusCode(), "Response should be returned")
+	assert.Equal(t, "value", *result.Key, "Result should be decoded")
+}
@@ -0,0 +1,286 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//ErrCircuitOpen is matched by errors.Is for Error returned when request
+//was rejected by open circuit breaker without being sent
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+//CircuitState describes state of a circuit breaker
+type CircuitState int
+
+const (
+	//CircuitClosed state lets all requests through
+	CircuitClosed CircuitState = iota
+	//CircuitOpen state rejects all requests
+	CircuitOpen
+	//CircuitHalfOpen state lets limited number of probe requests through
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+//CircuitBreakerConfig is used to describe circuit breaker behavior
+type CircuitBreakerConfig struct {
+	//FailureThreshold is a number of consecutive failures that opens a circuit
+	FailureThreshold int
+	//OpenTimeout is a time after which open circuit becomes half-open
+	OpenTimeout time.Duration
+	//HalfOpenRequests is a number of concurrent probe requests allowed in half-open state
+	HalfOpenRequests int
+	//KeyFunc determines circuit key for a request with a given method and URL, defaults to HostKey
+	KeyFunc func(method string, url string) string
+	//IsFailure determines whether request outcome counts as failure. By default transport errors,
+	//429 Too Many Requests and 5xx responses are failures. Requests cancelled with their own context
+	//are never counted
+	IsFailure func(resp *resty.Response, err error) bool
+	//OnStateChange is called when circuit with a given key changes its state
+	OnStateChange func(key string, from CircuitState, to CircuitState)
+}
+
+//DefaultCircuitBreakerConfig returns CircuitBreakerConfig with default values
+func DefaultCircuitBreakerConfig() *CircuitBreakerConfig {
+	return &CircuitBreakerConfig{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+		KeyFunc:          HostKey,
+		IsFailure:        isCircuitFailure,
+	}
+}
+
+//SetFailureThreshold sets number of consecutive failures that opens a circuit
+func (c *CircuitBreakerConfig) SetFailureThreshold(v int) *CircuitBreakerConfig {
+	c.FailureThreshold = v
+	return c
+}
+
+//SetOpenTimeout sets time after which open circuit becomes half-open
+func (c *CircuitBreakerConfig) SetOpenTimeout(v time.Duration) *CircuitBreakerConfig {
+	c.OpenTimeout = v
+	return c
+}
+
+//SetHalfOpenRequests sets number of concurrent probe requests allowed in half-open state
+func (c *CircuitBreakerConfig) SetHalfOpenRequests(v int) *CircuitBreakerConfig {
+	c.HalfOpenRequests = v
+	return c
+}
+
+//SetKeyFunc sets function that determines circuit key for a request
+func (c *CircuitBreakerConfig) SetKeyFunc(v func(method string, url string) string) *CircuitBreakerConfig {
+	c.KeyFunc = v
+	return c
+}
+
+//SetIsFailure sets function that determines whether request outcome counts as failure
+func (c *CircuitBreakerConfig) SetIsFailure(v func(resp *resty.Response, err error) bool) *CircuitBreakerConfig {
+	c.IsFailure = v
+	return c
+}
+
+//SetOnStateChange sets function called on circuit state changes
+func (c *CircuitBreakerConfig) SetOnStateChange(v func(key string, from CircuitState, to CircuitState)) *CircuitBreakerConfig {
+	c.OnStateChange = v
+	return c
+}
+
+//SetCircuitBreaker enables circuit breaker that rejects requests with ErrCircuitOpen
+//error after failure threshold is reached for a circuit key. Nil configuration disables the feature
+func (c *Client) SetCircuitBreaker(conf *CircuitBreakerConfig) *Client {
+	if conf == nil {
+		c.breaker = nil
+		return c
+	}
+	c.breaker = &circuitBreaker{
+		conf:     conf,
+		circuits: make(map[string]*circuit),
+		now:      time.Now,
+	}
+	return c
+}
+
+//CircuitState returns state of a circuit with a given key
+func (c *Client) CircuitState(key string) CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.state(key)
+}
+
+//HostKey is circuit key function that uses request's URL host as a key
+func HostKey(method string, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Host
+}
+
+//RouteKey returns circuit key function that uses first path template, like
+///ne/v1/devices/{uuid}, matching request's URL path as a key. Host is used as a key
+//for requests not matching any template
+func RouteKey(templates ...string) func(method string, url string) string {
+	return func(method string, rawURL string) string {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return rawURL
+		}
+		for _, template := range templates {
+			if matchPathTemplate(template, u.EscapedPath()) {
+				return u.Host + template
+			}
+		}
+		return u.Host
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type circuitBreaker struct {
+	conf     *CircuitBreakerConfig
+	mu       sync.Mutex
+	circuits map[string]*circuit
+	now      func() time.Time
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+type stateChange struct {
+	from CircuitState
+	to   CircuitState
+}
+
+func (b *circuitBreaker) key(method string, url string) string {
+	if b.conf.KeyFunc == nil {
+		return HostKey(method, url)
+	}
+	return b.conf.KeyFunc(method, url)
+}
+
+func (b *circuitBreaker) state(key string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.circuits[key]; ok {
+		return c.state
+	}
+	return CircuitClosed
+}
+
+func (b *circuitBreaker) allow(key string) error {
+	b.mu.Lock()
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{}
+		b.circuits[key] = c
+	}
+	var change *stateChange
+	if c.state == CircuitOpen && !b.now().Before(c.openedAt.Add(b.conf.OpenTimeout)) {
+		change = c.transition(CircuitHalfOpen, b.now())
+	}
+	allowed := true
+	switch c.state {
+	case CircuitOpen:
+		allowed = false
+	case CircuitHalfOpen:
+		if c.probes >= b.conf.HalfOpenRequests {
+			allowed = false
+		} else {
+			c.probes++
+		}
+	}
+	b.mu.Unlock()
+	b.notify(key, change)
+	if !allowed {
+		return Error{Message: fmt.Sprintf("circuit breaker for %q is open", key), cause: ErrCircuitOpen}
+	}
+	return nil
+}
+
+//record updates circuit with outcome of a request. Requests aborted by their own
+//context, i.e. cancelled by the caller, are neither failures nor successes
+func (b *circuitBreaker) record(ctx context.Context, key string, resp *resty.Response, err error) {
+	if err != nil && ctx.Err() != nil && errors.Is(err, ctx.Err()) {
+		b.mu.Lock()
+		if c := b.circuits[key]; c.state == CircuitHalfOpen && c.probes > 0 {
+			c.probes--
+		}
+		b.mu.Unlock()
+		return
+	}
+	isFailure := b.conf.IsFailure
+	if isFailure == nil {
+		isFailure = isCircuitFailure
+	}
+	failed := isFailure(resp, err)
+	b.mu.Lock()
+	c := b.circuits[key]
+	var change *stateChange
+	switch {
+	case c.state == CircuitHalfOpen && failed:
+		change = c.transition(CircuitOpen, b.now())
+	case c.state == CircuitHalfOpen:
+		change = c.transition(CircuitClosed, b.now())
+	case failed:
+		c.failures++
+		if c.state == CircuitClosed && c.failures >= b.conf.FailureThreshold {
+			change = c.transition(CircuitOpen, b.now())
+		}
+	default:
+		c.failures = 0
+	}
+	b.mu.Unlock()
+	b.notify(key, change)
+}
+
+func (b *circuitBreaker) notify(key string, change *stateChange) {
+	if change != nil && b.conf.OnStateChange != nil {
+		b.conf.OnStateChange(key, change.from, change.to)
+	}
+}
+
+func (c *circuit) transition(to CircuitState, now time.Time) *stateChange {
+	change := &stateChange{from: c.state, to: to}
+	c.state = to
+	c.failures = 0
+	c.probes = 0
+	if to == CircuitOpen {
+		c.openedAt = now
+	}
+	return change
+}
+
+func isCircuitFailure(resp *resty.Response, err error) bool {
+	if resp == nil || resp.RawResponse == nil {
+		return err != nil
+	}
+	return resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= http.StatusInternalServerError
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	//given
+	resourcePath := "/devices"
+	respCode := http.StatusBadGateway
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			return httpmock.NewStringResponse(respCode, ""), nil
+		},
+	)
+	var changes []CircuitState
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCircuitBreaker(DefaultCircuitBreakerConfig().
+		SetFailureThreshold(2).
+		SetOpenTimeout(time.Minute).
+		SetOnStateChange(func(key string, from CircuitState, to CircuitState) {
+			changes = append(changes, to)
+		}))
+	now := time.Now()
+	cli.breaker.now = func() time.Time { return now }
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	openErr := cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	callsWhenOpen := mock.GetTotalCallCount()
+	now = now.Add(time.Minute)
+	respCode = http.StatusOK
+	probeErr := cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	//then
+	assert.True(t, errors.Is(openErr, ErrCircuitOpen), "Open circuit should reject request")
+	assert.IsType(t, Error{}, openErr, "Error should be rest.Error type")
+	assert.Equal(t, 2, callsWhenOpen, "Rejected request should not be sent")
+	assert.Nil(t, probeErr, "Probe request should succeed")
+	assert.Equal(t, []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}, changes, "State changes should be reported")
+	assert.Equal(t, CircuitClosed, cli.CircuitState("localhost:8888"), "Circuit should be closed")
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	//given
+	resourcePath := "/devices"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath, httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCircuitBreaker(DefaultCircuitBreakerConfig().SetFailureThreshold(1).SetOpenTimeout(time.Minute))
+	now := time.Now()
+	cli.breaker.now = func() time.Time { return now }
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	now = now.Add(time.Minute)
+	probeErr := cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	//then
+	assert.False(t, errors.Is(probeErr, ErrCircuitOpen), "Probe request should be sent")
+	assert.Equal(t, CircuitOpen, cli.CircuitState("localhost:8888"), "Failed probe should open circuit")
+}
+
+func TestRouteKey(t *testing.T) {
+	//given
+	keyFunc := RouteKey("/ne/v1/devices/{uuid}", "/ne/v1/devices")
+	//when
+	deviceKey := keyFunc(resty.MethodGet, baseURL+"/ne/v1/devices/abc?view=full")
+	devicesKey := keyFunc(resty.MethodPost, baseURL+"/ne/v1/devices")
+	otherKey := keyFunc(resty.MethodGet, baseURL+"/fabric/v4/connections")
+	//then
+	assert.Equal(t, "localhost:8888/ne/v1/devices/{uuid}", deviceKey, "Device key matches")
+	assert.Equal(t, "localhost:8888/ne/v1/devices", devicesKey, "Devices key matches")
+	assert.Equal(t, "localhost:8888", otherKey, "Host should be used for unmatched path")
+}
+
+func TestCircuitBreakerIgnoresCancellation(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		return nil, r.Context().Err()
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cli := NewClient(ctx, baseURL, &http.Client{Transport: mock})
+	cli.SetCircuitBreaker(DefaultCircuitBreakerConfig().SetFailureThreshold(1))
+	//when
+	firstErr := cli.Execute(cli.R(), resty.MethodGet, "/devices")
+	secondErr := cli.Execute(cli.R(), resty.MethodGet, "/devices")
+	//then
+	assert.True(t, errors.Is(firstErr, context.Canceled), "Cancellation error should be returned")
+	assert.False(t, errors.Is(secondErr, ErrCircuitOpen), "Circuit should not open on cancellation")
+	assert.Equal(t, CircuitClosed, cli.CircuitState(HostKey(resty.MethodGet, baseURL+"/devices")), "Circuit should stay closed")
+}
@@ -0,0 +1,327 @@
+package rest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//CacheEntry describes cached response of HTTP GET request
+type CacheEntry struct {
+	//StatusCode is HTTP status code of cached response
+	StatusCode int
+	//Header holds cached response headers
+	Header http.Header
+	//Body is cached response body
+	Body []byte
+	//ETag is entity tag validator of cached response
+	ETag string
+	//LastModified is last modification date validator of cached response
+	LastModified string
+	//Expires is a time until cached response can be served without revalidation
+	Expires time.Time
+}
+
+//CacheStorage describes storage for cached responses
+type CacheStorage interface {
+	//Get returns entry stored under given key
+	Get(key string) (*CacheEntry, bool)
+	//Set stores entry under given key
+	Set(key string, entry *CacheEntry)
+	//Delete removes entry stored under given key
+	Delete(key string)
+}
+
+//SetCache enables caching of responses for HTTP GET requests. Cached responses
+//are revalidated with If-None-Match and If-Modified-Since headers and served from
+//given storage when server responds with 304 Not Modified.
+//Cache-Control directives no-store, no-cache and max-age are honoured. Nil storage disables caching.
+//Hop-by-hop headers and headers carrying secrets, like Set-Cookie, are not stored.
+//Entries are keyed by URL and Authorization header of outgoing request. Authorization added
+//below the client, i.e. by oauth2 transport of given http client, is not visible to the cache,
+//use SetCacheIdentity when storage is shared by clients with different identities.
+//Cache wraps current transport of underlying http client, so SetTransport, SetProxy and
+//SetTLSClientConfig are meant to be called before; calling SetTransport afterwards replaces the cache
+func (c *Client) SetCache(storage CacheStorage) *Client {
+	c.cache = storage
+	c.buildTransport()
+	return c
+}
+
+//SetCacheIdentity sets function that returns identity, like API client ID, on whose behalf
+//given request is made. Identity is a part of cache keys, so responses cached for one identity
+//are never served to other. Nil function keys entries by Authorization header only
+func (c *Client) SetCacheIdentity(identity func(req *http.Request) string) *Client {
+	c.cacheIdentity = identity
+	c.buildTransport()
+	return c
+}
+
+//NewMemoryCacheStorage creates in-memory cache storage that holds up to given
+//number of entries and evicts least recently used ones
+func NewMemoryCacheStorage(capacity int) CacheStorage {
+	return &memoryCacheStorage{entries: newLRUCache(capacity)}
+}
+
+//NewDiskCacheStorage creates cache storage that keeps entries as files
+//in a given directory. Directory is created if it does not exist
+func NewDiskCacheStorage(dir string) (CacheStorage, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("cannot create cache directory: %s", err)
+	}
+	return &diskCacheStorage{dir: dir}, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+//uncachedHeaders are hop-by-hop headers, headers that carry secrets and rate limit
+//headers, which describe quota at the time of a response and become stale
+var uncachedHeaders = append([]string{"Connection", "Keep-Alive", "Proxy-Authenticate", "Proxy-Authorization",
+	"TE", "Trailer", "Transfer-Encoding", "Upgrade", "Set-Cookie", "Cookie", "Authorization", "X-Auth-Token"},
+	rateLimitHeaders...)
+
+type cachingTransport struct {
+	storage  CacheStorage
+	identity func(req *http.Request) string
+	next     http.RoundTripper
+	now      func() time.Time
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := cacheKey(req, t.identity)
+	if req.Method != http.MethodGet {
+		resp, err := t.next.RoundTrip(req)
+		if err == nil && resp.StatusCode < http.StatusBadRequest {
+			t.storage.Delete(key)
+		}
+		return resp, err
+	}
+	reqDirectives := parseCacheControl(req.Header)
+	if _, ok := reqDirectives["no-store"]; ok {
+		return t.next.RoundTrip(req)
+	}
+	entry, cached := t.storage.Get(key)
+	if _, ok := reqDirectives["no-cache"]; ok {
+		cached = false
+	}
+	if cached && t.now().Before(entry.Expires) {
+		return entry.response(req), nil
+	}
+	outReq := req
+	if cached {
+		outReq = req.Clone(req.Context())
+		if entry.ETag != "" {
+			outReq.Header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			outReq.Header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+	resp, err := t.next.RoundTrip(outReq)
+	if err != nil {
+		return resp, err
+	}
+	if cached && resp.StatusCode == http.StatusNotModified {
+		resp.Body.Close()
+		updated := *entry
+		updated.Header = entry.Header.Clone()
+		for k, v := range cacheableHeader(resp.Header) {
+			updated.Header[k] = v
+		}
+		updated.Expires = t.expires(resp.Header)
+		t.storage.Set(key, &updated)
+		revalidated := updated.response(req)
+		for _, name := range rateLimitHeaders {
+			if values := resp.Header.Values(name); len(values) > 0 {
+				revalidated.Header[http.CanonicalHeaderKey(name)] = values
+			}
+		}
+		return revalidated, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	directives := parseCacheControl(resp.Header)
+	if _, ok := directives["no-store"]; ok {
+		t.storage.Delete(key)
+		return resp, nil
+	}
+	newEntry := &CacheEntry{
+		StatusCode:   resp.StatusCode,
+		Header:       cacheableHeader(resp.Header),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Expires:      t.expires(resp.Header),
+	}
+	if newEntry.ETag == "" && newEntry.LastModified == "" && !t.now().Before(newEntry.Expires) {
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	newEntry.Body = body
+	t.storage.Set(key, newEntry)
+	return resp, nil
+}
+
+func (t *cachingTransport) expires(header http.Header) time.Time {
+	directives := parseCacheControl(header)
+	if _, ok := directives["no-cache"]; ok {
+		return time.Time{}
+	}
+	if maxAge, ok := directives["max-age"]; ok {
+		if seconds, err := strconv.Atoi(maxAge); err == nil && seconds > 0 {
+			return t.now().Add(time.Duration(seconds) * time.Second)
+		}
+	}
+	return time.Time{}
+}
+
+func (e *CacheEntry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode)),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func cacheKey(req *http.Request, identity func(req *http.Request) string) string {
+	key := req.URL.String()
+	secret := req.Header.Get("Authorization")
+	if identity != nil {
+		secret += "|" + identity(req)
+	}
+	if secret != "" {
+		sum := sha256.Sum256([]byte(secret))
+		key += "#" + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+//cacheableHeader returns copy of given header without hop-by-hop headers, including
+//ones listed in Connection header, and headers that carry secrets
+func cacheableHeader(header http.Header) http.Header {
+	cacheable := header.Clone()
+	for _, value := range header.Values("Connection") {
+		for _, name := range strings.Split(value, ",") {
+			cacheable.Del(strings.TrimSpace(name))
+		}
+	}
+	for _, name := range uncachedHeaders {
+		cacheable.Del(name)
+	}
+	return cacheable
+}
+
+func parseCacheControl(header http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, value := range header.Values("Cache-Control") {
+		for _, part := range strings.Split(value, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, arg := part, ""
+			if i := strings.IndexByte(part, '='); i >= 0 {
+				name, arg = part[:i], strings.Trim(part[i+1:], "\"")
+			}
+			directives[strings.ToLower(name)] = arg
+		}
+	}
+	return directives
+}
+
+type memoryCacheStorage struct {
+	mu      sync.Mutex
+	entries *lruCache
+}
+
+func (s *memoryCacheStorage) Get(key string) (*CacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries.get(key)
+	if !ok {
+		return nil, false
+	}
+	return entry.(*CacheEntry), true
+}
+
+func (s *memoryCacheStorage) Set(key string, entry *CacheEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries.set(key, entry)
+}
+
+func (s *memoryCacheStorage) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries.delete(key)
+}
+
+type diskCacheStorage struct {
+	dir string
+}
+
+func (s *diskCacheStorage) Get(key string) (*CacheEntry, bool) {
+	data, err := ioutil.ReadFile(s.path(key))
+	if err != nil {
+		return nil, false
+	}
+	entry := &CacheEntry{}
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, false
+	}
+	return entry, true
+}
+
+func (s *diskCacheStorage) Set(key string, entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := ioutil.TempFile(s.dir, "entry-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), s.path(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (s *diskCacheStorage) Delete(key string) {
+	os.Remove(s.path(key))
+}
+
+func (s *diskCacheStorage) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,220 @@
+package rest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheRevalidation(t *testing.T) {
+	//given
+	etag := `"v1"`
+	resourcePath := "/objects/1"
+	mock := httpmock.NewMockTransport()
+	notModifiedCount := 0
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			if r.Header.Get("If-None-Match") == etag {
+				notModifiedCount++
+				return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+			}
+			resp, _ := httpmock.NewJsonResponse(200, TestObject{Key: stringPtr("cached")})
+			resp.Header.Set("ETag", etag)
+			return resp, nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCache(NewMemoryCacheStorage(10))
+	//when
+	results := make([]TestObject, 3)
+	for i := range results {
+		req := cli.R().SetResult(&results[i])
+		if err := cli.Execute(req, resty.MethodGet, resourcePath); err != nil {
+			assert.Failf(t, "request failed", "error %s", err)
+		}
+	}
+	//then
+	assert.Equal(t, 3, mock.GetTotalCallCount(), "Every request should be revalidated")
+	assert.Equal(t, 2, notModifiedCount, "Subsequent requests should be revalidated with If-None-Match")
+	for i := range results {
+		assert.Equalf(t, "cached", *results[i].Key, "Result %d should be decoded from cache", i)
+	}
+}
+
+func TestCacheMaxAge(t *testing.T) {
+	//given
+	resourcePath := "/objects/1"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp, _ := httpmock.NewJsonResponse(200, TestObject{Key: stringPtr("fresh")})
+			resp.Header.Set("Cache-Control", "private, max-age=60")
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodDelete, baseURL+resourcePath, httpmock.NewStringResponder(204, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCache(NewMemoryCacheStorage(10))
+	//when
+	_, err := cli.Do(resty.MethodGet, resourcePath, cli.R())
+	assert.Nil(t, err, "Error should not be returned")
+	_, err = cli.Do(resty.MethodGet, resourcePath, cli.R())
+	assert.Nil(t, err, "Error should not be returned")
+	getCountBeforeDelete := mock.GetCallCountInfo()["GET "+baseURL+resourcePath]
+	_, err = cli.Do(resty.MethodDelete, resourcePath, cli.R())
+	assert.Nil(t, err, "Error should not be returned")
+	_, err = cli.Do(resty.MethodGet, resourcePath, cli.R())
+	assert.Nil(t, err, "Error should not be returned")
+	//then
+	assert.Equal(t, 1, getCountBeforeDelete, "Fresh response should be served from cache")
+	assert.Equal(t, 2, mock.GetCallCountInfo()["GET "+baseURL+resourcePath], "Mutation should invalidate cache")
+}
+
+func TestCacheNoStore(t *testing.T) {
+	//given
+	resourcePath := "/objects/1"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, "{}")
+			resp.Header.Set("ETag", `"v1"`)
+			resp.Header.Set("Cache-Control", "no-store")
+			return resp, nil
+		},
+	)
+	storage := NewMemoryCacheStorage(10)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCache(storage)
+	//when
+	_, err := cli.Do(resty.MethodGet, resourcePath, cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	_, ok := storage.Get(baseURL + resourcePath)
+	assert.False(t, ok, "Response with no-store should not be cached")
+}
+
+func TestMemoryCacheStorageEviction(t *testing.T) {
+	//given
+	storage := NewMemoryCacheStorage(2)
+	//when
+	storage.Set("a", &CacheEntry{ETag: "a"})
+	storage.Set("b", &CacheEntry{ETag: "b"})
+	storage.Get("a")
+	storage.Set("c", &CacheEntry{ETag: "c"})
+	//then
+	_, okA := storage.Get("a")
+	_, okB := storage.Get("b")
+	_, okC := storage.Get("c")
+	assert.True(t, okA, "Recently used entry should be kept")
+	assert.False(t, okB, "Least recently used entry should be evicted")
+	assert.True(t, okC, "New entry should be kept")
+}
+
+func TestDiskCacheStorage(t *testing.T) {
+	//given
+	dir, err := ioutil.TempDir("", "rest-cache")
+	if err != nil {
+		assert.Failf(t, "cannot create temp dir", "error %s", err)
+	}
+	defer os.RemoveAll(dir)
+	storage, err := NewDiskCacheStorage(dir)
+	assert.Nil(t, err, "Error should not be returned")
+	entry := &CacheEntry{
+		StatusCode: 200,
+		Header:     http.Header{"Etag": []string{`"v1"`}},
+		Body:       []byte(`{"key":"value"}`),
+		ETag:       `"v1"`,
+	}
+	//when
+	storage.Set(baseURL+"/objects", entry)
+	stored, ok := storage.Get(baseURL + "/objects")
+	storage.Delete(baseURL + "/objects")
+	_, okAfterDelete := storage.Get(baseURL + "/objects")
+	//then
+	assert.True(t, ok, "Entry should be stored")
+	assert.Equal(t, entry, stored, "Stored entry should match")
+	assert.False(t, okAfterDelete, "Entry should be deleted")
+}
+
+func TestCacheIdentity(t *testing.T) {
+	//given
+	resourcePath := "/objects/1"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp, _ := httpmock.NewJsonResponse(200, TestObject{Key: stringPtr(r.Header.Get("X-Client"))})
+			resp.Header.Set("Cache-Control", "max-age=60")
+			resp.Header.Set("Set-Cookie", "session=secret")
+			resp.Header.Set("Connection", "X-Hop")
+			resp.Header.Set("X-Hop", "hop")
+			return resp, nil
+		},
+	)
+	storage := NewMemoryCacheStorage(10)
+	identity := func(req *http.Request) string {
+		return req.Header.Get("X-Client")
+	}
+	first := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	first.SetCache(storage).SetCacheIdentity(identity).SetHeader("X-Client", "first")
+	second := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	second.SetCache(storage).SetCacheIdentity(identity).SetHeader("X-Client", "second")
+	firstResult := TestObject{}
+	secondResult := TestObject{}
+	//when
+	firstErr := first.Execute(first.R().SetResult(&firstResult), resty.MethodGet, resourcePath)
+	secondErr := second.Execute(second.R().SetResult(&secondResult), resty.MethodGet, resourcePath)
+	//then
+	assert.Nil(t, firstErr, "Error should not be returned")
+	assert.Nil(t, secondErr, "Error should not be returned")
+	assert.Equal(t, 2, mock.GetTotalCallCount(), "Response of one identity should not be served to other")
+	assert.Equal(t, "second", *secondResult.Key, "Response for second identity should be returned")
+	req, _ := http.NewRequest(resty.MethodGet, baseURL+resourcePath, nil)
+	req.Header.Set("X-Client", "first")
+	if entry, ok := storage.Get(cacheKey(req, identity)); assert.True(t, ok, "Response should be cached") {
+		assert.Empty(t, entry.Header.Get("Set-Cookie"), "Secret headers should not be cached")
+		assert.Empty(t, entry.Header.Get("X-Hop"), "Hop-by-hop headers should not be cached")
+		assert.Equal(t, "max-age=60", entry.Header.Get("Cache-Control"), "Other headers should be cached")
+	}
+}
+
+func stringPtr(v string) *string {
+	return &v
+}
+
+func TestCacheWrapsCurrentTransport(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, "{}"))
+	custom := httpmock.NewMockTransport()
+	custom.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, "{}"))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetTransport(custom)
+	//when
+	cli.SetCache(NewMemoryCacheStorage(10))
+	err := cli.Execute(cli.R(), resty.MethodGet, "/objects")
+	cli.SetCache(nil)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 1, custom.GetTotalCallCount(), "Transport set before enabling cache should be used")
+	assert.Equal(t, 0, mock.GetTotalCallCount(), "Replaced transport should not be used")
+	assert.Equal(t, custom, cli.GetClient().Transport, "Disabling cache should restore current transport")
+}
+
+func TestCacheKeepsProxySetBefore(t *testing.T) {
+	//given
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: &http.Transport{}})
+	cli.SetProxy("http://proxy.example.com:3128")
+	//when
+	cli.SetCache(NewMemoryCacheStorage(10)).SetCache(nil)
+	//then
+	transport, ok := cli.GetClient().Transport.(*http.Transport)
+	if assert.True(t, ok, "Base transport should be restored") {
+		assert.NotNil(t, transport.Proxy, "Proxy set before enabling cache should be kept")
+	}
+}
@@ -0,0 +1,334 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	//ConfigFileEnvVar is OS variable name that holds path of a configuration file
+	ConfigFileEnvVar = "EQUINIX_CONFIG_FILE"
+	//ProfileEnvVar is OS variable name that holds name of a configuration file profile
+	ProfileEnvVar = "EQUINIX_PROFILE"
+	//EndpointEnvVar is OS variable name that holds API endpoint base URL
+	EndpointEnvVar = "EQUINIX_API_ENDPOINT"
+	//ClientIDEnvVar is OS variable name that holds API client identifier
+	ClientIDEnvVar = "EQUINIX_API_CLIENTID"
+	//ClientSecretEnvVar is OS variable name that holds API client secret
+	ClientSecretEnvVar = "EQUINIX_API_CLIENTSECRET"
+	//TokenEnvVar is OS variable name that holds API bearer token
+	TokenEnvVar = "EQUINIX_API_TOKEN"
+	//TimeoutEnvVar is OS variable name that holds HTTP request timeout,
+	//either as a duration, like 30s, or as a number of seconds
+	TimeoutEnvVar = "EQUINIX_API_TIMEOUT"
+	//MaxRetriesEnvVar is OS variable name that holds maximum number of request retries
+	MaxRetriesEnvVar = "EQUINIX_API_MAX_RETRIES"
+	//PageSizeEnvVar is OS variable name that holds page size for paginated queries
+	PageSizeEnvVar = "EQUINIX_API_PAGE_SIZE"
+	//ProxyEnvVar is OS variable name that holds HTTP proxy URL
+	ProxyEnvVar = "EQUINIX_API_PROXY"
+	//EnvironmentEnvVar is OS variable name that holds environment label, like sandbox or production
+	EnvironmentEnvVar = "EQUINIX_ENVIRONMENT"
+	//ProtectedEnvVar is OS variable name that determines, with true or false value,
+	//whether environment is protected from mutating requests
+	ProtectedEnvVar = "EQUINIX_PROTECTED"
+
+	//DefaultProfile is a name of a configuration file profile used when none is selected
+	DefaultProfile = "default"
+)
+
+//Config describes Equinix REST client configuration
+type Config struct {
+	//Endpoint is API endpoint base URL
+	Endpoint string
+	//ClientID is API client identifier, used by callers to obtain tokens
+	ClientID string
+	//ClientSecret is API client secret, used by callers to obtain tokens
+	ClientSecret string
+	//Token is bearer token sent with each request
+	Token string
+	//Timeout is HTTP request timeout, zero means no timeout
+	Timeout time.Duration
+	//MaxRetries is maximum number of request retries
+	MaxRetries int
+	//PageSize is page size for paginated queries
+	PageSize int
+	//Proxy is HTTP proxy URL
+	Proxy string
+	//LogLevel is logging level, DEBUG enables request and response logging. INFO, WARN and ERROR
+	//are accepted and have no effect, client logs warnings and errors regardless of the level
+	LogLevel string
+	//Environment is environment label, like sandbox or production
+	Environment string
+	//Protected determines whether environment is protected from mutating requests
+	Protected bool
+}
+
+//ConfigFile describes structure of YAML or JSON configuration file with named profiles
+type ConfigFile struct {
+	//DefaultProfile is a name of a profile used when none is selected
+	DefaultProfile string `json:"defaultProfile" yaml:"defaultProfile"`
+	//Profiles maps profile names to profile configurations
+	Profiles map[string]ConfigProfile `json:"profiles" yaml:"profiles"`
+}
+
+//ConfigProfile describes single profile of a configuration file.
+//Fields that are not set do not override other configuration sources
+type ConfigProfile struct {
+	Endpoint     string `json:"endpoint" yaml:"endpoint"`
+	ClientID     string `json:"clientId" yaml:"clientId"`
+	ClientSecret string `json:"clientSecret" yaml:"clientSecret"`
+	Token        string `json:"token" yaml:"token"`
+	//Timeout is either a duration, like 30s, or a number of seconds
+	Timeout    string `json:"timeout" yaml:"timeout"`
+	MaxRetries *int   `json:"maxRetries" yaml:"maxRetries"`
+	PageSize   *int   `json:"pageSize" yaml:"pageSize"`
+	Proxy      string `json:"proxy" yaml:"proxy"`
+	LogLevel   string `json:"logLevel" yaml:"logLevel"`
+	//Environment is environment label, defaults to profile name
+	Environment string `json:"environment" yaml:"environment"`
+	Protected   *bool  `json:"protected" yaml:"protected"`
+}
+
+//DefaultConfig returns Config with default values
+func DefaultConfig() *Config {
+	return &Config{
+		Timeout:    30 * time.Second,
+		MaxRetries: 0,
+		PageSize:   100,
+	}
+}
+
+//LoadConfig loads client configuration. Values are taken, in order of precedence, from:
+//EQUINIX_* environment variables, selected profile of a configuration file and defaults.
+//Configuration file path is given path or, when empty, value of EQUINIX_CONFIG_FILE;
+//file is optional when neither is set. Files with .json extension are parsed as JSON,
+//others as YAML. Profile is given profile or, when empty, value of EQUINIX_PROFILE,
+//file's default profile or "default". Loaded configuration is validated
+func LoadConfig(path string, profile string) (*Config, error) {
+	return loadConfig(osEnvProvider{}, path, profile)
+}
+
+//NewClientFromConfig creates new Equinix REST client with configuration loaded
+//by LoadConfig from a given configuration file path and profile
+func NewClientFromConfig(ctx context.Context, path string, profile string) (*Client, error) {
+	conf, err := LoadConfig(path, profile)
+	if err != nil {
+		return nil, err
+	}
+	return NewClientWithConfig(ctx, conf)
+}
+
+//NewClientWithConfig creates new Equinix REST client with a given configuration
+func NewClientWithConfig(ctx context.Context, conf *Config) (*Client, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Timeout: conf.Timeout}
+	if conf.Proxy != "" {
+		proxyURL, _ := url.Parse(conf.Proxy)
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(proxyURL)
+		httpClient.Transport = transport
+	}
+	c := NewClient(ctx, conf.Endpoint, httpClient)
+	c.SetPageSize(conf.PageSize)
+	c.SetRetryCount(conf.MaxRetries)
+	c.SetDebug(strings.EqualFold(conf.LogLevel, "DEBUG"))
+	if conf.Token != "" {
+		c.SetAuthToken(conf.Token)
+	}
+	c.SetEnvironment(conf.Environment, conf.Protected)
+	return c, nil
+}
+
+//Validate checks configuration values and returns error describing all invalid ones
+func (c *Config) Validate() error {
+	var problems []string
+	if c.Endpoint == "" {
+		problems = append(problems, "endpoint is required")
+	} else if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		problems = append(problems, fmt.Sprintf("endpoint %q is not an absolute HTTP URL", c.Endpoint))
+	} else if u.RawQuery != "" || u.Fragment != "" {
+		problems = append(problems, fmt.Sprintf("endpoint %q must not contain query or fragment", c.Endpoint))
+	}
+	if (c.ClientID == "") != (c.ClientSecret == "") {
+		problems = append(problems, "client ID and client secret must be set together")
+	}
+	if c.Timeout < 0 {
+		problems = append(problems, fmt.Sprintf("timeout %s must not be negative", c.Timeout))
+	}
+	if c.MaxRetries < 0 {
+		problems = append(problems, fmt.Sprintf("max retries %d must not be negative", c.MaxRetries))
+	}
+	if c.PageSize < 1 {
+		problems = append(problems, fmt.Sprintf("page size %d must be positive", c.PageSize))
+	}
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil || u.Scheme == "" || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("proxy %q is not an absolute URL", c.Proxy))
+		}
+	}
+	switch strings.ToUpper(c.LogLevel) {
+	case "", "DEBUG", "INFO", "WARN", "ERROR":
+	default:
+		problems = append(problems, fmt.Sprintf("log level %q is not one of DEBUG, INFO, WARN, ERROR", c.LogLevel))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid client configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func loadConfig(env envProvider, path string, profile string) (*Config, error) {
+	conf := DefaultConfig()
+	if path == "" {
+		path = env.getEnv(ConfigFileEnvVar)
+	}
+	if profile == "" {
+		profile = env.getEnv(ProfileEnvVar)
+	}
+	if path != "" {
+		file, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if profile == "" {
+			profile = file.DefaultProfile
+		}
+		if profile == "" {
+			profile = DefaultProfile
+		}
+		p, ok := file.Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("configuration file %q has no profile %q", path, profile)
+		}
+		conf.Environment = profile
+		if err := conf.applyProfile(p); err != nil {
+			return nil, fmt.Errorf("configuration file %q profile %q is invalid: %s", path, profile, err)
+		}
+	} else if profile != "" {
+		return nil, fmt.Errorf("profile %q is selected but no configuration file is given", profile)
+	}
+	if err := conf.applyEnv(env); err != nil {
+		return nil, err
+	}
+	if err := conf.Validate(); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
+func readConfigFile(path string) (*ConfigFile, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read configuration file: %s", err)
+	}
+	file := &ConfigFile{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(file)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse configuration file %q: %s", path, err)
+	}
+	return file, nil
+}
+
+func (c *Config) applyProfile(p ConfigProfile) error {
+	setString(&c.Endpoint, p.Endpoint)
+	setString(&c.ClientID, p.ClientID)
+	setString(&c.ClientSecret, p.ClientSecret)
+	setString(&c.Token, p.Token)
+	setString(&c.Proxy, p.Proxy)
+	setString(&c.LogLevel, p.LogLevel)
+	setString(&c.Environment, p.Environment)
+	if p.Protected != nil {
+		c.Protected = *p.Protected
+	}
+	if p.Timeout != "" {
+		timeout, err := parseTimeout(p.Timeout)
+		if err != nil {
+			return err
+		}
+		c.Timeout = timeout
+	}
+	if p.MaxRetries != nil {
+		c.MaxRetries = *p.MaxRetries
+	}
+	if p.PageSize != nil {
+		c.PageSize = *p.PageSize
+	}
+	return nil
+}
+
+func (c *Config) applyEnv(env envProvider) error {
+	setString(&c.Endpoint, env.getEnv(EndpointEnvVar))
+	setString(&c.ClientID, env.getEnv(ClientIDEnvVar))
+	setString(&c.ClientSecret, env.getEnv(ClientSecretEnvVar))
+	setString(&c.Token, env.getEnv(TokenEnvVar))
+	setString(&c.Proxy, env.getEnv(ProxyEnvVar))
+	setString(&c.LogLevel, env.getEnv(LogLevelEnvVar))
+	setString(&c.Environment, env.getEnv(EnvironmentEnvVar))
+	if v := env.getEnv(ProtectedEnvVar); v != "" {
+		protected, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("environment variable %s is invalid: %q is not a boolean", ProtectedEnvVar, v)
+		}
+		c.Protected = protected
+	}
+	if v := env.getEnv(TimeoutEnvVar); v != "" {
+		timeout, err := parseTimeout(v)
+		if err != nil {
+			return fmt.Errorf("environment variable %s is invalid: %s", TimeoutEnvVar, err)
+		}
+		c.Timeout = timeout
+	}
+	for name, target := range map[string]*int{MaxRetriesEnvVar: &c.MaxRetries, PageSizeEnvVar: &c.PageSize} {
+		if v := env.getEnv(name); v != "" {
+			i, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("environment variable %s is invalid: %q is not an integer", name, v)
+			}
+			*target = i
+		}
+	}
+	return nil
+}
+
+func setString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+func parseTimeout(value string) (time.Duration, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("timeout %q is neither a duration nor a number of seconds", value)
+	}
+	return timeout, nil
+}
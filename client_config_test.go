@@ -0,0 +1,141 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigFileDefaultProfile(t *testing.T) {
+	//given
+	env := mockedEnvProvider{map[string]string{}}
+	//when
+	conf, err := loadConfig(env, "./test-fixtures/config.yaml", "")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, "https://sandboxapi.equinix.com", conf.Endpoint, "Endpoint matches")
+	assert.Equal(t, "sandbox-token", conf.Token, "Token matches")
+	assert.Equal(t, 10*time.Second, conf.Timeout, "Timeout matches")
+	assert.Equal(t, 50, conf.PageSize, "Page size matches")
+	assert.Equal(t, 0, conf.MaxRetries, "Default max retries should be used")
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	//given
+	env := mockedEnvProvider{map[string]string{
+		ConfigFileEnvVar: "./test-fixtures/config.yaml",
+		ProfileEnvVar:    "production",
+		PageSizeEnvVar:   "25",
+		TimeoutEnvVar:    "5s",
+	}}
+	//when
+	conf, err := loadConfig(env, "", "")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, "https://api.equinix.com", conf.Endpoint, "Endpoint from profile matches")
+	assert.Equal(t, "client", conf.ClientID, "Client ID from profile matches")
+	assert.Equal(t, 3, conf.MaxRetries, "Max retries from profile matches")
+	assert.Equal(t, "http://proxy.example.com:3128", conf.Proxy, "Proxy from profile matches")
+	assert.Equal(t, 25, conf.PageSize, "Page size from environment should take precedence")
+	assert.Equal(t, 5*time.Second, conf.Timeout, "Timeout from environment should take precedence")
+	assert.Equal(t, "production", conf.Environment, "Environment should default to profile name")
+	assert.True(t, conf.Protected, "Protection from profile matches")
+}
+
+func TestLoadConfigJSON(t *testing.T) {
+	//given
+	env := mockedEnvProvider{map[string]string{}}
+	//when
+	conf, err := loadConfig(env, "./test-fixtures/config.json", "")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, "https://api.equinix.com", conf.Endpoint, "Endpoint matches")
+	assert.Equal(t, 20, conf.PageSize, "Page size matches")
+}
+
+func TestLoadConfigEnvOnly(t *testing.T) {
+	//given
+	env := mockedEnvProvider{map[string]string{
+		EndpointEnvVar: "https://api.equinix.com",
+		TimeoutEnvVar:  "15",
+	}}
+	//when
+	conf, err := loadConfig(env, "", "")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, "https://api.equinix.com", conf.Endpoint, "Endpoint matches")
+	assert.Equal(t, 15*time.Second, conf.Timeout, "Timeout in seconds matches")
+	assert.Equal(t, 100, conf.PageSize, "Default page size should be used")
+	assert.Equal(t, "", conf.Environment, "Environment should not be set")
+	assert.False(t, conf.Protected, "Environment should not be protected")
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	testCases := map[string]struct {
+		env     map[string]string
+		path    string
+		profile string
+		message string
+	}{
+		"missing profile": {
+			path:    "./test-fixtures/config.yaml",
+			profile: "staging",
+			message: `configuration file "./test-fixtures/config.yaml" has no profile "staging"`,
+		},
+		"profile without file": {
+			profile: "staging",
+			message: `profile "staging" is selected but no configuration file is given`,
+		},
+		"invalid env integer": {
+			env:     map[string]string{EndpointEnvVar: "https://api.equinix.com", MaxRetriesEnvVar: "many"},
+			message: `environment variable EQUINIX_API_MAX_RETRIES is invalid: "many" is not an integer`,
+		},
+		"invalid values": {
+			env: map[string]string{
+				EndpointEnvVar: "api.equinix.com",
+				ClientIDEnvVar: "client",
+				PageSizeEnvVar: "0",
+				LogLevelEnvVar: "VERBOSE",
+			},
+			message: `invalid client configuration: endpoint "api.equinix.com" is not an absolute HTTP URL; ` +
+				`client ID and client secret must be set together; page size 0 must be positive; ` +
+				`log level "VERBOSE" is not one of DEBUG, INFO, WARN, ERROR`,
+		},
+	}
+	for name, tc := range testCases {
+		//given
+		env := mockedEnvProvider{tc.env}
+		//when
+		_, err := loadConfig(env, tc.path, tc.profile)
+		//then
+		if assert.NotNilf(t, err, "Error should be returned for %s", name) {
+			assert.Equalf(t, tc.message, err.Error(), "Error message for %s matches", name)
+		}
+	}
+}
+
+func TestNewClientWithConfig(t *testing.T) {
+	//given
+	conf := DefaultConfig()
+	conf.Endpoint = "https://api.equinix.com"
+	conf.Token = "token"
+	conf.MaxRetries = 2
+	conf.PageSize = 10
+	conf.Proxy = "http://proxy.example.com:3128"
+	//when
+	cli, err := NewClientWithConfig(context.Background(), conf)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 10, cli.PageSize, "Page size matches")
+	assert.Equal(t, 2, cli.RetryCount, "Retry count matches")
+	assert.Equal(t, "token", cli.Token, "Token matches")
+	assert.Equal(t, conf.Timeout, cli.GetClient().Timeout, "Timeout matches")
+	transport, ok := cli.GetClient().Transport.(*http.Transport)
+	if assert.True(t, ok, "Transport should be HTTP transport") {
+		proxyURL, _ := transport.Proxy(&http.Request{})
+		assert.Equal(t, "http://proxy.example.com:3128", proxyURL.String(), "Proxy matches")
+	}
+}
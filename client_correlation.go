@@ -0,0 +1,90 @@
+package rest
+
+import (
+	"context"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	//DefaultCorrelationIDHeader is a default name of a header that carries correlation ID
+	DefaultCorrelationIDHeader = "X-Correlation-ID"
+)
+
+//SetCorrelationIDHeader sets name of a header that carries correlation ID of requests
+//and responses. Correlation ID is taken from the request header, from the context given with
+//WithCorrelationID or is generated, and is shared by all requests of a logical operation:
+//retries, page requests of paginated queries and polls of WaitFor and followed operations.
+//Empty name disables the feature
+func (c *Client) SetCorrelationIDHeader(name string) *Client {
+	c.correlationIDHeader = name
+	return c
+}
+
+//WithCorrelationID returns context derived from a given one that carries correlation ID
+//sent with requests made with that context
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+//CorrelationID returns correlation ID returned by the server in a given response or,
+//when server did not return one, correlation ID that was sent with the request
+func (c *Client) CorrelationID(resp *resty.Response) string {
+	if c.correlationIDHeader == "" || resp == nil {
+		return ""
+	}
+	if resp.RawResponse != nil {
+		if id := resp.Header().Get(c.correlationIDHeader); id != "" {
+			return id
+		}
+	}
+	if resp.Request == nil {
+		return ""
+	}
+	return resp.Request.Header.Get(c.correlationIDHeader)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type correlationIDKey struct{}
+
+//correlationContext returns context carrying correlation ID of a logical operation,
+//taken from a given request, if any, or from given context, or generated
+func (c *Client) correlationContext(ctx context.Context, req *resty.Request) context.Context {
+	if c.correlationIDHeader == "" {
+		return ctx
+	}
+	if req != nil {
+		if id := req.Header.Get(c.correlationIDHeader); id != "" {
+			return WithCorrelationID(ctx, id)
+		}
+		if id, ok := req.Context().Value(correlationIDKey{}).(string); ok && id != "" {
+			return WithCorrelationID(ctx, id)
+		}
+	}
+	if id, ok := ctx.Value(correlationIDKey{}).(string); ok && id != "" {
+		return ctx
+	}
+	id, err := newUUID()
+	if err != nil {
+		return ctx
+	}
+	return WithCorrelationID(ctx, id)
+}
+
+func (c *Client) applyCorrelationID(ctx context.Context, req *resty.Request) string {
+	if c.correlationIDHeader == "" {
+		return ""
+	}
+	if id := req.Header.Get(c.correlationIDHeader); id != "" {
+		return id
+	}
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	if !ok || id == "" {
+		id, _ = newUUID()
+	}
+	req.SetHeader(c.correlationIDHeader, id)
+	return id
+}
@@ -0,0 +1,100 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationIDRetries(t *testing.T) {
+	//given
+	var ids []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		ids = append(ids, r.Header.Get(DefaultCorrelationIDHeader))
+		if len(ids) < 3 {
+			return httpmock.NewStringResponse(http.StatusServiceUnavailable, ""), nil
+		}
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetRetryCount(2).AddRetryCondition(func(r *resty.Response, err error) bool {
+		return r.StatusCode() == http.StatusServiceUnavailable
+	})
+	//when
+	resp, err := cli.Do(resty.MethodGet, "/objects", cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	if assert.Len(t, ids, 3, "Request should be retried") {
+		assert.NotEmpty(t, ids[0], "Correlation ID should be generated")
+		assert.Equal(t, ids[0], ids[1], "Retry should reuse correlation ID")
+		assert.Equal(t, ids[0], ids[2], "Retry should reuse correlation ID")
+	}
+	assert.Equal(t, ids[0], cli.CorrelationID(resp), "Sent correlation ID should be returned")
+}
+
+func TestCorrelationIDPages(t *testing.T) {
+	//given
+	var ids []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		ids = append(ids, r.Header.Get("X-Request-ID"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		total, key := 3, "value"
+		return httpmock.NewJsonResponse(http.StatusOK, TestOffsetPaginatedResponse{
+			Pagination: &TestPagination{Offset: &offset, Total: &total},
+			Data:       []TestObject{{Key: &key}},
+		})
+	})
+	ctx := WithCorrelationID(context.Background(), "operation-id")
+	cli := NewClient(ctx, baseURL, &http.Client{Transport: mock})
+	cli.SetCorrelationIDHeader("X-Request-ID").SetPageSize(1)
+	//when
+	data, err := cli.GetOffsetPaginated("/objects", &TestOffsetPaginatedResponse{}, DefaultOffsetPagingConfig())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Len(t, data, 3, "All pages should be fetched")
+	assert.Equal(t, []string{"operation-id", "operation-id", "operation-id"}, ids, "Page requests should share correlation ID from context")
+}
+
+func TestCorrelationIDError(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusInternalServerError, "")
+		resp.Header.Set(DefaultCorrelationIDHeader, "server-id")
+		return resp, nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	//when
+	err := cli.Execute(cli.R().SetHeader(DefaultCorrelationIDHeader, "client-id"), resty.MethodGet, "/objects")
+	//then
+	restErr := Error{}
+	if assert.True(t, errors.As(err, &restErr), "Error should be rest.Error type") {
+		assert.Equal(t, "server-id", restErr.CorrelationID, "Server correlation ID should be recorded")
+	}
+}
+
+func TestCorrelationIDDisabled(t *testing.T) {
+	//given
+	var header string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		header = r.Header.Get(DefaultCorrelationIDHeader)
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCorrelationIDHeader("")
+	//when
+	resp, err := cli.Do(resty.MethodGet, "/objects", cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Empty(t, header, "Correlation ID should not be sent")
+	assert.Empty(t, cli.CorrelationID(resp), "Correlation ID should not be returned")
+}
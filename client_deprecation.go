@@ -0,0 +1,159 @@
+package rest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//DeprecationNotice describes deprecation announced by the server for an endpoint
+//with Deprecation, Sunset or Warning response headers
+type DeprecationNotice struct {
+	//Method is HTTP method of a request
+	Method string
+	//Endpoint is matching path template or, when none matches, URL path of a request
+	Endpoint string
+	//Deprecation is a value of Deprecation header
+	Deprecation string
+	//DeprecatedAt is a time of deprecation, zero when Deprecation header has no date
+	DeprecatedAt time.Time
+	//Sunset is a time when endpoint becomes unavailable, zero when Sunset header is absent
+	Sunset time.Time
+	//Warnings are values of Warning headers
+	Warnings []string
+	//Links are values of Link headers with deprecation or sunset relation
+	Links []string
+}
+
+//SetDeprecationHandler sets function called when response announces deprecation with
+//Deprecation, Sunset or Warning headers. Each distinct notice is reported once per endpoint.
+//Request paths matching given path templates, like /ne/v1/devices/{uuid}, are reported
+//as a single endpoint. Notices for paths that match no template are reported once per method,
+//with Endpoint of the first request. Nil handler disables the feature
+func (c *Client) SetDeprecationHandler(handler func(notice DeprecationNotice), templates ...string) *Client {
+	if handler == nil {
+		c.deprecations = nil
+		return c
+	}
+	c.deprecations = &deprecationTracker{
+		handler:   handler,
+		templates: templates,
+		reported:  newLRUCache(deprecationTrackerCapacity),
+	}
+	return c
+}
+
+//DeprecationLogger returns deprecation handler that reports notices with a given logger
+func DeprecationLogger(logger resty.Logger) func(notice DeprecationNotice) {
+	return func(notice DeprecationNotice) {
+		status := "is deprecated"
+		if notice.Deprecation == "" && notice.Sunset.IsZero() {
+			status = "returned warnings"
+		}
+		msg := "Equinix REST API endpoint " + notice.Method + " " + notice.Endpoint + " " + status
+		if !notice.Sunset.IsZero() {
+			msg += ", sunset: " + notice.Sunset.Format(time.RFC3339)
+		}
+		if len(notice.Warnings) > 0 {
+			msg += ", warnings: " + strings.Join(notice.Warnings, "; ")
+		}
+		if len(notice.Links) > 0 {
+			msg += ", see: " + strings.Join(notice.Links, ", ")
+		}
+		logger.Warnf("%s", msg)
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+//deprecationTrackerCapacity is a maximum number of reported notices which are remembered
+const deprecationTrackerCapacity = 1000
+
+type deprecationTracker struct {
+	handler   func(notice DeprecationNotice)
+	templates []string
+	mu        sync.Mutex
+	reported  *lruCache
+}
+
+func (t *deprecationTracker) check(method string, rawURL string, resp *resty.Response) {
+	if resp == nil || resp.RawResponse == nil {
+		return
+	}
+	notice, ok := newDeprecationNotice(resp.Header())
+	if !ok {
+		return
+	}
+	notice.Method = method
+	var template string
+	notice.Endpoint, template = t.endpoint(rawURL)
+	key := strings.Join([]string{notice.Method, template, notice.Deprecation,
+		resp.Header().Get("Sunset"), strings.Join(notice.Warnings, "\n")}, "\x00")
+	t.mu.Lock()
+	if _, ok := t.reported.get(key); ok {
+		t.mu.Unlock()
+		return
+	}
+	t.reported.set(key, true)
+	t.mu.Unlock()
+	t.handler(notice)
+}
+
+//endpoint returns endpoint of a request and matching path template, which is
+//empty when no template matches, so such paths are not told apart
+func (t *deprecationTracker) endpoint(rawURL string) (string, string) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, ""
+	}
+	for _, template := range t.templates {
+		if matchPathTemplate(template, u.EscapedPath()) {
+			return template, template
+		}
+	}
+	return u.EscapedPath(), ""
+}
+
+func newDeprecationNotice(header http.Header) (DeprecationNotice, bool) {
+	notice := DeprecationNotice{
+		Deprecation: strings.TrimSpace(header.Get("Deprecation")),
+		Warnings:    header.Values("Warning"),
+	}
+	if notice.Deprecation != "" {
+		notice.DeprecatedAt = parseDeprecationDate(notice.Deprecation)
+	}
+	if sunset := header.Get("Sunset"); sunset != "" {
+		if t, err := http.ParseTime(sunset); err == nil {
+			notice.Sunset = t
+		}
+	}
+	for _, link := range header.Values("Link") {
+		if strings.Contains(link, `rel="deprecation"`) || strings.Contains(link, `rel="sunset"`) {
+			notice.Links = append(notice.Links, link)
+		}
+	}
+	found := notice.Deprecation != "" || header.Get("Sunset") != "" || len(notice.Warnings) > 0
+	return notice, found
+}
+
+//parseDeprecationDate parses Deprecation header value that is either structured date,
+//like @1688169599, or HTTP date. Zero time is returned for other values, like true
+func parseDeprecationDate(value string) time.Time {
+	if strings.HasPrefix(value, "@") {
+		if seconds, err := strconv.ParseInt(value[1:], 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC()
+		}
+		return time.Time{}
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,123 @@
+package rest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	warnings []string
+}
+
+func (l *testLogger) Errorf(format string, v ...interface{}) {}
+
+func (l *testLogger) Warnf(format string, v ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprintf(format, v...))
+}
+
+func (l *testLogger) Debugf(format string, v ...interface{}) {}
+
+func TestDeprecationHandler(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "{}")
+		if r.URL.Path != "/ne/v2/devices/1" {
+			resp.Header.Set("Deprecation", "@1688169599")
+			resp.Header.Set("Sunset", "Wed, 11 Nov 2026 23:59:59 GMT")
+			resp.Header.Add("Link", `<https://developer.equinix.com/migration>; rel="deprecation"`)
+		}
+		return resp, nil
+	})
+	var notices []DeprecationNotice
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetDeprecationHandler(func(notice DeprecationNotice) {
+		notices = append(notices, notice)
+	}, "/ne/v1/devices/{uuid}")
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices/1")
+	cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices/2")
+	cli.Execute(cli.R(), resty.MethodGet, "/ne/v2/devices/1")
+	cli.Execute(cli.R(), resty.MethodDelete, "/ne/v1/devices/1")
+	//then
+	if assert.Len(t, notices, 2, "Notices should be deduplicated per endpoint") {
+		assert.Equal(t, DeprecationNotice{
+			Method:       resty.MethodGet,
+			Endpoint:     "/ne/v1/devices/{uuid}",
+			Deprecation:  "@1688169599",
+			DeprecatedAt: time.Unix(1688169599, 0).UTC(),
+			Sunset:       time.Date(2026, time.November, 11, 23, 59, 59, 0, time.UTC),
+			Links:        []string{`<https://developer.equinix.com/migration>; rel="deprecation"`},
+		}, notices[0], "Notice matches")
+		assert.Equal(t, resty.MethodDelete, notices[1].Method, "Other method should be reported separately")
+	}
+}
+
+func TestDeprecationLogger(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "{}")
+		resp.Header.Add("Warning", `299 - "Parameter 'sort' is deprecated"`)
+		return resp, nil
+	})
+	logger := &testLogger{}
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetDeprecationHandler(DeprecationLogger(logger))
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, "/fabric/v4/connections")
+	cli.Execute(cli.R(), resty.MethodGet, "/fabric/v4/connections")
+	//then
+	assert.Equal(t, []string{
+		`Equinix REST API endpoint GET /fabric/v4/connections returned warnings, warnings: 299 - "Parameter 'sort' is deprecated"`,
+	}, logger.warnings, "Warning should be logged once")
+}
+
+func TestDeprecationHandlerWithoutTemplates(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "{}")
+		resp.Header.Set("Deprecation", "true")
+		return resp, nil
+	})
+	var notices []DeprecationNotice
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetDeprecationHandler(func(notice DeprecationNotice) {
+		notices = append(notices, notice)
+	})
+	cli.deprecations.reported.capacity = 2
+	//when
+	for i := 0; i < 5; i++ {
+		cli.Execute(cli.R(), resty.MethodGet, fmt.Sprintf("/ne/v1/devices/%d", i))
+	}
+	cli.Execute(cli.R(), resty.MethodPost, "/ne/v1/devices")
+	cli.Execute(cli.R(), resty.MethodDelete, "/ne/v1/devices/0")
+	//then
+	if assert.Len(t, notices, 3, "Notices should be deduplicated per method") {
+		assert.Equal(t, "/ne/v1/devices/0", notices[0].Endpoint, "Endpoint of first request should be reported")
+	}
+	assert.Equal(t, 2, cli.deprecations.reported.len(), "Number of remembered notices should be limited")
+}
+
+func TestParseDeprecationDate(t *testing.T) {
+	//given
+	values := map[string]time.Time{
+		"@1688169599":                   time.Unix(1688169599, 0).UTC(),
+		"Sun, 11 Nov 2018 23:59:59 GMT": time.Date(2018, time.November, 11, 23, 59, 59, 0, time.UTC),
+		"true":                          {},
+	}
+	for value, expected := range values {
+		//when
+		date := parseDeprecationDate(value)
+		//then
+		assert.Equalf(t, expected, date, "Date for %q matches", value)
+	}
+}
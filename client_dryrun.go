@@ -0,0 +1,129 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/equinix/rest-go/internal/redact"
+	"github.com/go-resty/resty/v2"
+)
+
+//PlannedRequest describes mutating request captured in dry-run mode
+type PlannedRequest struct {
+	//Method is HTTP method of a request
+	Method string `json:"method"`
+	//URL is request URL, including query string
+	URL string `json:"url"`
+	//Body is request body with secrets redacted
+	Body string `json:"body,omitempty"`
+}
+
+//SetDryRun enables or disables dry-run mode. In dry-run mode, POST, PUT, PATCH and DELETE
+//requests are not sent. Instead they are captured into a plan and 204 No Content response
+//is returned. Other requests are sent as usual. Enabling dry-run mode starts new plan
+func (c *Client) SetDryRun(enabled bool) *Client {
+	if !enabled {
+		c.dryRun = nil
+		return c
+	}
+	c.dryRun = &dryRunPlan{}
+	return c
+}
+
+//Plan returns mutating requests captured in dry-run mode, in order they were made
+func (c *Client) Plan() []PlannedRequest {
+	if c.dryRun == nil {
+		return nil
+	}
+	return c.dryRun.requests()
+}
+
+//WritePlan writes mutating requests captured in dry-run mode to a given writer as JSON
+func (c *Client) WritePlan(w io.Writer) error {
+	plan := c.Plan()
+	if plan == nil {
+		plan = []PlannedRequest{}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(plan)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type dryRunPlan struct {
+	mu      sync.Mutex
+	planned []PlannedRequest
+}
+
+func (p *dryRunPlan) add(r PlannedRequest) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.planned = append(p.planned, r)
+}
+
+func (p *dryRunPlan) requests() []PlannedRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	requests := make([]PlannedRequest, len(p.planned))
+	copy(requests, p.planned)
+	return requests
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case resty.MethodPost, resty.MethodPut, resty.MethodPatch, resty.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *Client) planRequest(method string, url string, req *resty.Request) (*resty.Response, error) {
+	body, err := requestBody(req)
+	if err != nil {
+		return nil, Error{Message: "cannot capture request body: " + err.Error()}
+	}
+	if query := req.QueryParam.Encode(); query != "" {
+		separator := "?"
+		if strings.Contains(url, "?") {
+			separator = "&"
+		}
+		url += separator + query
+	}
+	c.dryRun.add(PlannedRequest{
+		Method: method,
+		URL:    url,
+		Body:   string(redact.Body(body, redact.DefaultFields)),
+	})
+	return &resty.Response{
+		Request: req,
+		RawResponse: &http.Response{
+			Status:     fmt.Sprintf("%d %s", http.StatusNoContent, http.StatusText(http.StatusNoContent)),
+			StatusCode: http.StatusNoContent,
+			Header:     http.Header{},
+		},
+	}, nil
+}
+
+//requestBody returns serialized body of a given request, without sending it
+func requestBody(req *resty.Request) ([]byte, error) {
+	switch body := req.Body.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return body, nil
+	case string:
+		return []byte(body), nil
+	case io.Reader:
+		return nil, fmt.Errorf("body of type %T cannot be captured", body)
+	default:
+		return json.Marshal(body)
+	}
+}
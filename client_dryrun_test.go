@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRun(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewJsonResponderOrPanic(http.StatusOK, map[string]string{"key": "value"}))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetDryRun(true)
+	result := TestObject{}
+	body := map[string]string{"name": "device", "password": "secret"}
+	//when
+	getErr := cli.Execute(cli.R().SetResult(&result), resty.MethodGet, "/objects/1")
+	postResp, postErr := cli.Do(resty.MethodPost, "/devices", cli.R().SetBody(body).SetQueryParam("dryRun", "false"))
+	deleteErr := cli.Execute(cli.R(), resty.MethodDelete, "/devices/1")
+	plan := cli.Plan()
+	//then
+	assert.Nil(t, getErr, "GET request should be sent")
+	assert.Equal(t, "value", *result.Key, "GET result should be decoded")
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Mutating requests should not be sent")
+	assert.Nil(t, postErr, "Planned request should succeed")
+	assert.Equal(t, http.StatusNoContent, postResp.StatusCode(), "Synthetic response status matches")
+	assert.Nil(t, deleteErr, "Planned request should succeed")
+	assert.Equal(t, []PlannedRequest{
+		{Method: resty.MethodPost, URL: baseURL + "/devices?dryRun=false", Body: `{"name":"device","password":"REDACTED"}`},
+		{Method: resty.MethodDelete, URL: baseURL + "/devices/1"},
+	}, plan, "Plan matches")
+}
+
+func TestWritePlan(t *testing.T) {
+	//given
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: httpmock.NewMockTransport()})
+	cli.SetDryRun(true)
+	cli.Execute(cli.R().SetBody(`{"name":"device"}`), resty.MethodPut, "/devices/1")
+	buf := &bytes.Buffer{}
+	//when
+	err := cli.WritePlan(buf)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.JSONEq(t, `[{"method":"PUT","url":"`+baseURL+`/devices/1","body":"{\"name\":\"device\"}"}]`, buf.String(), "Plan report matches")
+}
+
+func TestDryRunQuery(t *testing.T) {
+	//given
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: httpmock.NewMockTransport()})
+	cli.SetDryRun(true)
+	//when
+	cli.Execute(cli.R().SetQueryParam("a", "b"), resty.MethodDelete, "/devices/1?force=true")
+	plan := cli.Plan()
+	//then
+	assert.Equal(t, []PlannedRequest{
+		{Method: resty.MethodDelete, URL: baseURL + "/devices/1?force=true&a=b"},
+	}, plan, "Query parameters should be appended to path query")
+}
@@ -0,0 +1,72 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//ErrProtectedEnvironment is matched by errors.Is for Error returned when mutating
+//request against protected environment was refused without being sent
+var ErrProtectedEnvironment = errors.New("mutating request refused in protected environment")
+
+//Environment describes Equinix API environment, like sandbox or production, client works with
+type Environment struct {
+	//Name is environment label
+	Name string
+	//Protected determines whether requests other than GET, HEAD and OPTIONS are refused
+	//unless explicitly allowed with AllowMutations or AllowMutation
+	Protected bool
+}
+
+//SetEnvironment sets environment label and protection mode of the client
+func (c *Client) SetEnvironment(name string, protected bool) *Client {
+	c.environment = Environment{Name: name, Protected: protected}
+	return c
+}
+
+//Environment returns environment of the client
+func (c *Client) Environment() Environment {
+	return c.environment
+}
+
+//AllowMutations returns context derived from a given one that allows mutating requests
+//against protected environment. It is meant for functions accepting context, like ExecuteBatch
+func AllowMutations(ctx context.Context) context.Context {
+	return context.WithValue(ctx, allowMutationsKey{}, true)
+}
+
+//AllowMutation marks given request as allowed to be sent against protected environment
+func AllowMutation(req *resty.Request) *resty.Request {
+	return req.SetContext(AllowMutations(req.Context()))
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type allowMutationsKey struct{}
+
+func (c *Client) guardEnvironment(ctx context.Context, method string, url string, req *resty.Request) error {
+	if !c.environment.Protected {
+		return nil
+	}
+	switch method {
+	case resty.MethodGet, resty.MethodHead, resty.MethodOptions:
+		return nil
+	}
+	if mutationsAllowed(ctx) || mutationsAllowed(req.Context()) {
+		return nil
+	}
+	return Error{
+		Message: fmt.Sprintf("%s %s refused: environment %q is protected", method, url, c.environment.Name),
+		cause:   ErrProtectedEnvironment,
+	}
+}
+
+func mutationsAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(allowMutationsKey{}).(bool)
+	return allowed
+}
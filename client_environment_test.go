@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProtectedEnvironment(t *testing.T) {
+	//given
+	resourcePath := "/connections/1"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNoContent, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetEnvironment("production", true)
+	//when
+	getErr := cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	deleteErr := cli.Execute(cli.R(), resty.MethodDelete, resourcePath)
+	callsAfterRefusal := mock.GetTotalCallCount()
+	allowedErr := cli.Execute(AllowMutation(cli.R()), resty.MethodDelete, resourcePath)
+	//then
+	assert.Nil(t, getErr, "GET request should be sent")
+	assert.NotNil(t, deleteErr, "DELETE request should be refused")
+	assert.IsType(t, Error{}, deleteErr, "Error should be rest.Error type")
+	assert.True(t, errors.Is(deleteErr, ErrProtectedEnvironment), "Error should be classified as protected environment")
+	assert.Contains(t, deleteErr.Error(), `environment \"production\" is protected`, "Error should name environment")
+	assert.Equal(t, 1, callsAfterRefusal, "Refused request should not be sent")
+	assert.Nil(t, allowedErr, "Allowed request should be sent")
+	assert.Equal(t, 2, mock.GetTotalCallCount(), "Allowed request should be sent")
+}
+
+func TestProtectedEnvironmentAllowedContext(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNoContent, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetEnvironment("production", true)
+	items := []BatchItem{{Method: resty.MethodDelete, Path: "/connections/1"}}
+	//when
+	_, refusedErr := cli.ExecuteBatch(context.Background(), items, nil)
+	_, allowedErr := cli.ExecuteBatch(AllowMutations(context.Background()), items, nil)
+	//then
+	assert.NotNil(t, refusedErr, "Batch should be refused without allowed context")
+	assert.Nil(t, allowedErr, "Batch should be sent with allowed context")
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Only allowed request should be sent")
+}
+
+func TestUnprotectedEnvironment(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusNoContent, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetEnvironment("sandbox", false)
+	//when
+	err := cli.Execute(cli.R(), resty.MethodDelete, "/connections/1")
+	//then
+	assert.Nil(t, err, "Request should be sent")
+	assert.Equal(t, Environment{Name: "sandbox"}, cli.Environment(), "Environment matches")
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//ErrPreconditionFailed is matched by errors.Is for Error returned when server
+//rejects conditional request with 412 Precondition Failed, i.e. because resource
+//was modified since its ETag was captured
+var ErrPreconditionFailed = errors.New("precondition failed")
+
+//SetOptimisticLocking enables or disables sending If-Match header with ETag captured
+//from the most recent response for the same path on PUT, PATCH and DELETE requests.
+//Requests that have If-Match header already set are left intact. Weak ETags are not
+//captured, as they never match If-Match condition
+func (c *Client) SetOptimisticLocking(enabled bool) *Client {
+	c.optimisticLocking = enabled
+	return c
+}
+
+//ETag returns entity tag captured from the most recent response for a given path.
+//Entity tags of up to 1000 most recently used paths are kept, weak entity tags are not captured
+func (c *Client) ETag(path string) (string, bool) {
+	url, err := joinURL(c.baseURL, path)
+	if err != nil {
+		return "", false
+	}
+	if url, _, err = c.versionURL(url, nil); err != nil {
+		return "", false
+	}
+	return c.etags.get(etagKey(url))
+}
+
+//SetIfMatch sets If-Match header on a given request with ETag captured
+//for a given path. Request is returned unchanged when no ETag was captured
+func (c *Client) SetIfMatch(req *resty.Request, path string) *resty.Request {
+	if etag, ok := c.ETag(path); ok {
+		req.SetHeader("If-Match", etag)
+	}
+	return req
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+//etagStoreCapacity is a maximum number of URLs which ETags are kept
+const etagStoreCapacity = 1000
+
+//etagStore keeps ETags of the most recently used URLs and evicts least recently used ones
+type etagStore struct {
+	mu    sync.Mutex
+	etags *lruCache
+}
+
+func newETagStore() *etagStore {
+	return &etagStore{etags: newLRUCache(etagStoreCapacity)}
+}
+
+func (s *etagStore) get(key string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag, ok := s.etags.get(key)
+	if !ok {
+		return "", false
+	}
+	return etag.(string), true
+}
+
+func (s *etagStore) set(key string, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if etag == "" {
+		s.etags.delete(key)
+		return
+	}
+	s.etags.set(key, etag)
+}
+
+func (c *Client) applyIfMatch(method string, url string, req *resty.Request) {
+	if !c.optimisticLocking || req.Header.Get("If-Match") != "" {
+		return
+	}
+	switch method {
+	case resty.MethodPut, resty.MethodPatch, resty.MethodDelete:
+		if etag, ok := c.etags.get(etagKey(url)); ok {
+			req.SetHeader("If-Match", etag)
+		}
+	}
+}
+
+//captureETag keeps strong ETag of a response. Weak ETags, like W/"v1", are dropped
+//as If-Match uses strong comparison and server would reject them with 412
+func (c *Client) captureETag(method string, url string, resp *resty.Response) {
+	key := etagKey(url)
+	switch method {
+	case resty.MethodGet, resty.MethodPut, resty.MethodPatch:
+		etag := resp.Header().Get("ETag")
+		if strings.HasPrefix(etag, "W/") {
+			etag = ""
+		}
+		c.etags.set(key, etag)
+	case resty.MethodDelete:
+		c.etags.set(key, "")
+	}
+}
+
+func etagKey(url string) string {
+	if i := strings.IndexByte(url, '?'); i >= 0 {
+		return url[:i]
+	}
+	return url
+}
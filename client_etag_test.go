@@ -0,0 +1,134 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptimisticLocking(t *testing.T) {
+	//given
+	etag := `"v1"`
+	resourcePath := "/objects/1"
+	var receivedIfMatch string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, "{}")
+			resp.Header.Set("ETag", etag)
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodPut, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			receivedIfMatch = r.Header.Get("If-Match")
+			return httpmock.NewStringResponse(204, ""), nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetOptimisticLocking(true)
+	//when
+	_, err := cli.Do(resty.MethodGet, resourcePath+"?view=full", cli.R())
+	assert.Nil(t, err, "Error should not be returned")
+	capturedETag, ok := cli.ETag(resourcePath)
+	_, err = cli.Do(resty.MethodPut, resourcePath, cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.True(t, ok, "ETag should be captured")
+	assert.Equal(t, etag, capturedETag, "Captured ETag matches")
+	assert.Equal(t, etag, receivedIfMatch, "If-Match header should be sent")
+}
+
+func TestSetIfMatch(t *testing.T) {
+	//given
+	resourcePath := "/objects/1"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, "{}")
+			resp.Header.Set("ETag", `"v2"`)
+			return resp, nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	//when
+	_, err := cli.Do(resty.MethodGet, resourcePath, cli.R())
+	req := cli.SetIfMatch(cli.R(), resourcePath)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, `"v2"`, req.Header.Get("If-Match"), "If-Match header should be set")
+}
+
+func TestPreconditionFailedError(t *testing.T) {
+	//given
+	resourcePath := "/objects/1"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPatch, baseURL+resourcePath,
+		httpmock.NewStringResponder(http.StatusPreconditionFailed, `{"errorCode":"IC-412","errorMessage":"modified"}`))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	//when
+	err := cli.Execute(cli.R().SetHeader("If-Match", `"v1"`), resty.MethodPatch, resourcePath)
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.True(t, errors.Is(err, ErrPreconditionFailed), "Error should be classified as precondition failed")
+	assert.False(t, errors.Is(Error{HTTPCode: 500}, ErrPreconditionFailed), "Other errors should not be classified as precondition failed")
+}
+
+func TestOptimisticLockingWeakETag(t *testing.T) {
+	//given
+	resourcePath := "/objects/1"
+	etags := []string{`"v1"`, `W/"v2"`}
+	var receivedIfMatch []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(200, "{}")
+			resp.Header.Set("ETag", etags[0])
+			etags = etags[1:]
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodPut, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			receivedIfMatch = r.Header.Values("If-Match")
+			return httpmock.NewStringResponse(204, ""), nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetOptimisticLocking(true)
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	_, ok := cli.ETag(resourcePath)
+	err := cli.Execute(cli.R(), resty.MethodPut, resourcePath)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.False(t, ok, "Weak ETag should not be captured")
+	assert.Empty(t, receivedIfMatch, "If-Match header should not be sent")
+}
+
+func TestETagStoreEviction(t *testing.T) {
+	//given
+	store := newETagStore()
+	store.etags.capacity = 2
+	//when
+	store.set("/devices/1", `"v1"`)
+	store.set("/devices/2", `"v2"`)
+	store.get("/devices/1")
+	store.set("/devices/3", `"v3"`)
+	store.set("/devices/1", "")
+	//then
+	_, ok := store.get("/devices/2")
+	assert.False(t, ok, "Least recently used ETag should be evicted")
+	_, ok = store.get("/devices/1")
+	assert.False(t, ok, "Cleared ETag should be removed")
+	etag, ok := store.get("/devices/3")
+	assert.True(t, ok, "Recent ETag should be kept")
+	assert.Equal(t, `"v3"`, etag, "ETag matches")
+	assert.Equal(t, 1, store.etags.len(), "Only one ETag should be kept")
+}
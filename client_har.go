@@ -0,0 +1,310 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/equinix/rest-go/internal/redact"
+)
+
+const (
+	//DefaultHARMaxEntries is a default maximum number of HAR entries kept by the client
+	DefaultHARMaxEntries = 1000
+)
+
+//SetHARRecording enables or disables recording of client's HTTP traffic in HTTP Archive
+//(HAR 1.2) format. Secrets in headers, query parameters and JSON bodies are redacted.
+//Responses served from cache without contacting the server are not recorded. Only the most
+//recent entries are kept, see SetHARMaxEntries. Enabling recording discards previously recorded traffic.
+//Recorder wraps current transport of underlying http client, so SetTransport, SetProxy and
+//SetTLSClientConfig are meant to be called before; calling SetTransport afterwards replaces the recorder
+func (c *Client) SetHARRecording(enabled bool) *Client {
+	c.har = nil
+	if enabled {
+		c.har = &harRecorder{now: time.Now, maxEntries: c.harMaxEntries}
+	}
+	c.buildTransport()
+	return c
+}
+
+//SetHARMaxEntries sets maximum number of recorded HAR entries. When limit is reached,
+//the oldest entries are discarded. Zero means no limit
+func (c *Client) SetHARMaxEntries(max int) *Client {
+	c.harMaxEntries = max
+	if c.har != nil {
+		c.har.setMaxEntries(max)
+	}
+	return c
+}
+
+//ResetHAR discards traffic recorded so far
+func (c *Client) ResetHAR() *Client {
+	if c.har != nil {
+		c.har.reset()
+	}
+	return c
+}
+
+//WriteHAR writes traffic recorded so far to a given writer as HAR 1.2 document
+func (c *Client) WriteHAR(w io.Writer) error {
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "equinix-rest-go", Version: Version},
+		Entries: []harEntry{},
+	}}
+	if c.har != nil {
+		doc.Log.Entries = c.har.entriesCopy()
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
+
+//DumpHAR writes traffic recorded so far to a file with a given path as HAR 1.2 document
+func (c *Client) DumpHAR(path string) error {
+	buf := &bytes.Buffer{}
+	if err := c.WriteHAR(buf); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, buf.Bytes(), 0600)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type harRecorder struct {
+	now        func() time.Time
+	mu         sync.Mutex
+	maxEntries int
+	entries    []harEntry
+	start      int
+}
+
+type harTransport struct {
+	recorder *harRecorder
+	next     http.RoundTripper
+}
+
+func (t *harTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := t.recorder
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	start := r.now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := r.now().Sub(start)
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            durationMillis(elapsed),
+		Request:         newHARRequest(req, reqBody),
+		Timings:         harTimings{Send: 0, Wait: durationMillis(elapsed), Receive: 0},
+	}
+	if err != nil {
+		entry.Response = harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+		entry.Comment = err.Error()
+		r.add(entry)
+		return resp, err
+	}
+	respBody, readErr := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	entry.Response = newHARResponse(resp, respBody)
+	if readErr != nil {
+		entry.Comment = readErr.Error()
+	}
+	r.add(entry)
+	return resp, readErr
+}
+
+//add appends given entry, replacing the oldest one when maximum number of entries is reached
+func (r *harRecorder) add(entry harEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.maxEntries > 0 && len(r.entries) >= r.maxEntries {
+		r.entries[r.start] = entry
+		r.start = (r.start + 1) % len(r.entries)
+		return
+	}
+	r.entries = append(r.entries, entry)
+}
+
+func (r *harRecorder) entriesCopy() []harEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.ordered()
+}
+
+func (r *harRecorder) setMaxEntries(max int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := r.ordered()
+	if max > 0 && len(entries) > max {
+		entries = entries[len(entries)-max:]
+	}
+	r.entries = entries
+	r.start = 0
+	r.maxEntries = max
+}
+
+func (r *harRecorder) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+	r.start = 0
+}
+
+//ordered returns copy of entries from the oldest to the most recent one
+func (r *harRecorder) ordered() []harEntry {
+	entries := make([]harEntry, len(r.entries))
+	n := copy(entries, r.entries[r.start:])
+	copy(entries[n:], r.entries[:r.start])
+	return entries
+}
+
+func newHARRequest(req *http.Request, body []byte) harRequest {
+	redactedURL := redact.URL(req.URL, redact.DefaultQueryParams)
+	harReq := harRequest{
+		Method:      req.Method,
+		URL:         redactedURL.String(),
+		HTTPVersion: httpVersion(req.Proto),
+		Cookies:     []harNameValue{},
+		Headers:     harNameValues(redact.Header(req.Header, redact.DefaultHeaders)),
+		QueryString: harNameValues(redactedURL.Query()),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if len(body) > 0 {
+		harReq.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     string(redact.Body(body, redact.DefaultFields)),
+		}
+	}
+	return harReq
+}
+
+func newHARResponse(resp *http.Response, body []byte) harResponse {
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: httpVersion(resp.Proto),
+		Cookies:     []harNameValue{},
+		Headers:     harNameValues(redact.Header(resp.Header, redact.DefaultHeaders)),
+		Content: harContent{
+			Size:     len(body),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     string(redact.Body(body, redact.DefaultFields)),
+		},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+func harNameValues(values map[string][]string) []harNameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, value := range values[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	return pairs
+}
+
+func httpVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,117 @@
+package rest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHARRecording(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+"/users",
+		httpmock.NewStringResponder(http.StatusBadRequest, `[{"errorCode":"EQ-1","errorMessage":"invalid"}]`))
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, `{"key":"value"}`))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetHARRecording(true).SetAuthToken("secret-token")
+	//when
+	cli.Execute(cli.R().SetQueryParam("offset", "20"), resty.MethodGet, "/objects")
+	cli.Execute(cli.R().SetBody(map[string]string{"name": "user", "password": "secret"}), resty.MethodPost, "/users")
+	buf := &bytes.Buffer{}
+	err := cli.WriteHAR(buf)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.NotContains(t, buf.String(), "secret", "Secrets should be redacted")
+	doc := harDocument{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &doc), "HAR should be valid JSON")
+	assert.Equal(t, "1.2", doc.Log.Version, "HAR version matches")
+	assert.Equal(t, Version, doc.Log.Creator.Version, "Creator version matches")
+	if assert.Len(t, doc.Log.Entries, 2, "Each request should be recorded") {
+		get := doc.Log.Entries[0]
+		assert.Equal(t, baseURL+"/objects?offset=20", get.Request.URL, "Request URL matches")
+		assert.Equal(t, []harNameValue{{Name: "offset", Value: "20"}}, get.Request.QueryString, "Query string matches")
+		assert.Equal(t, http.StatusOK, get.Response.Status, "Response status matches")
+		assert.Equal(t, `{"key":"value"}`, get.Response.Content.Text, "Response content matches")
+		post := doc.Log.Entries[1]
+		assert.Equal(t, `{"name":"user","password":"REDACTED"}`, post.Request.PostData.Text, "Request body should be redacted")
+		assert.Equal(t, http.StatusBadRequest, post.Response.Status, "Error response status matches")
+	}
+}
+
+func TestDumpHAR(t *testing.T) {
+	//given
+	dir, err := ioutil.TempDir("", "har")
+	if err != nil {
+		assert.Failf(t, "cannot create temporary directory", "%s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "traffic.har")
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, `{}`))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetHARRecording(true)
+	cli.Execute(cli.R(), resty.MethodGet, "/objects")
+	//when
+	err = cli.DumpHAR(path)
+	cli.SetHARRecording(false)
+	cli.Execute(cli.R(), resty.MethodGet, "/objects")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	data, _ := ioutil.ReadFile(path)
+	doc := harDocument{}
+	assert.Nil(t, json.Unmarshal(data, &doc), "HAR file should be valid JSON")
+	assert.Len(t, doc.Log.Entries, 1, "Recorded request should be dumped")
+	assert.Equal(t, mock, cli.GetClient().Transport, "Disabling recording should restore transport")
+}
+
+func TestHARRecordingWithCache(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, `{}`)
+		resp.Header.Set("Cache-Control", "max-age=60")
+		return resp, nil
+	})
+	httpClient := &http.Client{Transport: mock}
+	cli := NewClient(context.Background(), baseURL, httpClient)
+	//when
+	cli.SetCache(NewMemoryCacheStorage(10)).SetHARRecording(true).SetCache(nil)
+	cli.Execute(cli.R(), resty.MethodGet, "/objects")
+	cli.Execute(cli.R(), resty.MethodGet, "/objects")
+	callsWithoutCache := mock.GetTotalCallCount()
+	cli.SetHARRecording(false)
+	//then
+	assert.Equal(t, 2, callsWithoutCache, "Disabled cache should not serve responses")
+	assert.Equal(t, mock, cli.GetClient().Transport, "Disabling all middlewares should restore transport")
+	assert.Equal(t, mock, httpClient.Transport, "Given http client should not be modified")
+}
+
+func TestHARMaxEntries(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, `{}`))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetHARRecording(true).SetHARMaxEntries(2)
+	//when
+	for _, path := range []string{"/objects/1", "/objects/2", "/objects/3"} {
+		cli.Execute(cli.R().SetQueryParam("access_token", "secret"), resty.MethodGet, path)
+	}
+	entries := cli.har.entriesCopy()
+	cli.ResetHAR()
+	//then
+	if assert.Len(t, entries, 2, "Only the most recent entries should be kept") {
+		assert.Equal(t, baseURL+"/objects/2?access_token=REDACTED", entries[0].Request.URL, "Oldest kept entry URL matches")
+		assert.Equal(t, baseURL+"/objects/3?access_token=REDACTED", entries[1].Request.URL, "Most recent entry URL matches")
+		assert.Equal(t, []harNameValue{{Name: "access_token", Value: "REDACTED"}}, entries[1].Request.QueryString, "Query string should be redacted")
+	}
+	assert.Empty(t, cli.har.entriesCopy(), "Reset should discard recorded traffic")
+}
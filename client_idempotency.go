@@ -0,0 +1,61 @@
+package rest
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/go-resty/resty/v2"
+)
+
+const (
+	//DefaultIdempotencyKeyHeader is a default name of a header that carries idempotency key
+	DefaultIdempotencyKeyHeader = "Idempotency-Key"
+)
+
+//SetIdempotencyKeyHeader enables attaching generated idempotency key in a header with
+//a given name to POST and PATCH requests. Same key is sent on every retry of the request.
+//Keys already set on a request by the caller are preserved. Empty name disables the feature
+func (c *Client) SetIdempotencyKeyHeader(name string) *Client {
+	c.idempotencyKeyHeader = name
+	return c
+}
+
+//IdempotencyKey returns idempotency key that was sent with a request of a given response
+func (c *Client) IdempotencyKey(resp *resty.Response) string {
+	if c.idempotencyKeyHeader == "" || resp == nil || resp.Request == nil {
+		return ""
+	}
+	return resp.Request.Header.Get(c.idempotencyKeyHeader)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (c *Client) applyIdempotencyKey(method string, req *resty.Request) (string, error) {
+	if c.idempotencyKeyHeader == "" {
+		return "", nil
+	}
+	if method != resty.MethodPost && method != resty.MethodPatch {
+		return "", nil
+	}
+	if key := req.Header.Get(c.idempotencyKeyHeader); key != "" {
+		return key, nil
+	}
+	key, err := newUUID()
+	if err != nil {
+		return "", fmt.Errorf("cannot generate idempotency key: %s", err)
+	}
+	req.SetHeader(c.idempotencyKeyHeader, key)
+	return key, nil
+}
+
+func newUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
@@ -0,0 +1,64 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyKeyRetries(t *testing.T) {
+	//given
+	resourcePath := "/objects"
+	var receivedKeys []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			receivedKeys = append(receivedKeys, r.Header.Get(DefaultIdempotencyKeyHeader))
+			if len(receivedKeys) == 1 {
+				return nil, errors.New("connection timed out")
+			}
+			return httpmock.NewStringResponse(201, "{}"), nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetIdempotencyKeyHeader(DefaultIdempotencyKeyHeader).SetRetryCount(1)
+	//when
+	resp, err := cli.Do(resty.MethodPost, resourcePath, cli.R().SetBody(map[string]string{"name": "test"}))
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 2, len(receivedKeys), "Request should be retried")
+	assert.Regexp(t, regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$"), receivedKeys[0], "Key should be UUID")
+	assert.Equal(t, receivedKeys[0], receivedKeys[1], "Same key should be sent on retry")
+	assert.Equal(t, receivedKeys[0], cli.IdempotencyKey(resp), "Key should be exposed on response")
+}
+
+func TestIdempotencyKeyOnError(t *testing.T) {
+	//given
+	resourcePath := "/objects"
+	header := "X-Request-Key"
+	callerKey := "my-key"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPatch, baseURL+resourcePath, httpmock.NewStringResponder(500, ""))
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			assert.Empty(t, r.Header.Get(header), "Key should not be sent on GET")
+			return httpmock.NewStringResponse(200, "{}"), nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetIdempotencyKeyHeader(header)
+	//when
+	_, getErr := cli.Do(resty.MethodGet, resourcePath, cli.R())
+	_, err := cli.Do(resty.MethodPatch, resourcePath, cli.R().SetHeader(header, callerKey))
+	//then
+	assert.Nil(t, getErr, "Error should not be returned")
+	assert.NotNil(t, err, "Error should be returned")
+	assert.IsType(t, Error{}, err, "Error should be rest.Error type")
+	assert.Equal(t, callerKey, err.(Error).IdempotencyKey, "Caller key should be exposed on error")
+}
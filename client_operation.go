@@ -0,0 +1,164 @@
+package rest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//OperationConfig is used to describe how asynchronous operations, announced
+//with 202 Accepted responses, are followed until completion
+type OperationConfig struct {
+	//LocationHeaders is a list of response headers, checked in order, that point at operation resource
+	LocationHeaders []string
+	//ErrorsFieldName is a name of operation resource field that holds application errors of failed operation
+	ErrorsFieldName string
+	//Timeout limits time of following single operation, zero means no limit
+	Timeout time.Duration
+	//Wait controls polling of operation resource status
+	Wait *WaitConfig
+}
+
+//DefaultOperationConfig returns OperationConfig with default values
+func DefaultOperationConfig() *OperationConfig {
+	return &OperationConfig{
+		LocationHeaders: []string{"Operation-Location", "Location"},
+		ErrorsFieldName: "errors",
+		Timeout:         30 * time.Minute,
+		Wait: DefaultWaitConfig().
+			SetStatusFieldName("status").
+			SetSuccessStatuses("SUCCEEDED", "COMPLETED").
+			SetFailureStatuses("FAILED", "CANCELLED"),
+	}
+}
+
+//SetLocationHeaders sets response headers that point at operation resource
+func (c *OperationConfig) SetLocationHeaders(v ...string) *OperationConfig {
+	c.LocationHeaders = v
+	return c
+}
+
+//SetErrorsFieldName sets application errors field name
+func (c *OperationConfig) SetErrorsFieldName(v string) *OperationConfig {
+	c.ErrorsFieldName = v
+	return c
+}
+
+//SetTimeout sets time limit of following single operation
+func (c *OperationConfig) SetTimeout(v time.Duration) *OperationConfig {
+	c.Timeout = v
+	return c
+}
+
+//SetWait sets configuration of operation resource status polling
+func (c *OperationConfig) SetWait(v *WaitConfig) *OperationConfig {
+	c.Wait = v
+	return c
+}
+
+//SetOperationFollowing enables following of asynchronous operations. When enabled, Do
+//polls operation resource pointed by 202 Accepted response until operation completes.
+//Completed operation is decoded into request's result, unless it points at final resource
+//with Location header, which is then retrieved instead. Failed operation is returned
+//as Error with operation's application errors. Empty location headers and nil wait configuration
+//are replaced with defaults. Nil configuration disables the feature
+func (c *Client) SetOperationFollowing(conf *OperationConfig) *Client {
+	c.operationConfig = conf
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (c *Client) followOperation(ctx context.Context, accepted *resty.Response, req *resty.Request) (*resty.Response, error) {
+	conf := *c.operationConfig
+	defaults := DefaultOperationConfig()
+	if len(conf.LocationHeaders) == 0 {
+		conf.LocationHeaders = defaults.LocationHeaders
+	}
+	if conf.Wait == nil {
+		conf.Wait = defaults.Wait
+	}
+	opURL, ok, err := c.operationURL(accepted, conf.LocationHeaders)
+	if err != nil {
+		return accepted, Error{HTTPCode: accepted.StatusCode(), Message: err.Error()}
+	}
+	if !ok {
+		return accepted, nil
+	}
+	if conf.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, conf.Timeout)
+		defer cancel()
+	}
+	operation := make(map[string]interface{})
+	resp, err := c.poll(ctx, opURL, &operation, conf.Wait)
+	if err != nil {
+		return resp, operationError(err, operation, conf.ErrorsFieldName)
+	}
+	resourceURL, ok, err := c.operationURL(resp, []string{"Location"})
+	if err != nil {
+		return resp, Error{HTTPCode: resp.StatusCode(), Message: err.Error()}
+	}
+	if ok {
+		resourceReq := c.R().SetResult(req.Result)
+		resourceURL, version, err := c.versionURL(resourceURL, resourceReq)
+		if err != nil {
+			return resp, err
+		}
+		resp, err = c.execute(ctx, resty.MethodGet, resourceURL, resourceReq)
+		return resp, version.check(resp, err)
+	}
+	if req.Result != nil {
+		if err := json.Unmarshal(resp.Body(), req.Result); err != nil {
+			return resp, Error{HTTPCode: resp.StatusCode(), Message: "cannot decode completed operation: " + err.Error()}
+		}
+	}
+	return resp, nil
+}
+
+func (c *Client) operationURL(resp *resty.Response, headers []string) (string, bool, error) {
+	for _, header := range headers {
+		location := resp.Header().Get(header)
+		if location == "" {
+			continue
+		}
+		base, err := url.Parse(c.baseURL)
+		if err != nil {
+			return "", false, fmt.Errorf("base URL %q is invalid: %s", c.baseURL, err)
+		}
+		ref, err := url.Parse(location)
+		if err != nil {
+			return "", false, fmt.Errorf("operation location %q is invalid: %s", location, err)
+		}
+		resolved := base.ResolveReference(ref)
+		if resolved.Scheme != base.Scheme || resolved.Host != base.Host {
+			return "", false, fmt.Errorf("operation location %q points outside of %q", location, c.baseURL)
+		}
+		return resolved.String(), true, nil
+	}
+	return "", false, nil
+}
+
+func operationError(err error, operation map[string]interface{}, errorsFieldName string) error {
+	var restErr Error
+	if errors.As(err, &restErr) {
+		return restErr
+	}
+	if !errors.Is(err, ErrFailureStatus) && !errors.Is(err, ErrUnexpectedStatus) {
+		return Error{Message: "following operation failed: " + err.Error(), cause: err}
+	}
+	restErr = Error{Message: err.Error(), cause: err}
+	if appErrors, ok := operation[errorsFieldName]; ok {
+		if body, err := json.Marshal(appErrors); err == nil {
+			restErr.ApplicationErrors, _ = mapErrorBodyAPIToDomain(body)
+		}
+	}
+	return restErr
+}
@@ -0,0 +1,143 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOperationFollowing(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+"/devices",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusAccepted, "")
+			resp.Header.Set("Location", "/operations/1")
+			return resp, nil
+		},
+	)
+	polls := 0
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/operations/1",
+		func(r *http.Request) (*http.Response, error) {
+			polls++
+			if polls < 2 {
+				return httpmock.NewJsonResponse(200, map[string]string{"status": "RUNNING"})
+			}
+			resp, _ := httpmock.NewJsonResponse(200, map[string]string{"status": "SUCCEEDED"})
+			resp.Header.Set("Location", baseURL+"/devices/1")
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/devices/1",
+		httpmock.NewJsonResponderOrPanic(200, TestDevice{UUID: stringPtr("1"), Status: stringPtr("PROVISIONED")}))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultOperationConfig()
+	conf.Wait.SetInterval(time.Millisecond, time.Millisecond)
+	cli.SetOperationFollowing(conf)
+	result := TestDevice{}
+	//when
+	resp, err := cli.Do(resty.MethodPost, "/devices", cli.R().SetResult(&result))
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 200, resp.StatusCode(), "Final resource response should be returned")
+	assert.Equal(t, 2, polls, "Operation should be polled until completion")
+	assert.Equal(t, "PROVISIONED", *result.Status, "Final resource should be decoded")
+}
+
+func TestOperationFollowingFailure(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodDelete, baseURL+"/devices/1",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusAccepted, "")
+			resp.Header.Set("Operation-Location", "/operations/2")
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/operations/2",
+		httpmock.NewJsonResponderOrPanic(200, map[string]interface{}{
+			"status": "FAILED",
+			"errors": []map[string]string{{"errorCode": "IC-NE-01", "errorMessage": "device is locked"}},
+		}))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetOperationFollowing(DefaultOperationConfig())
+	//when
+	_, err := cli.Do(resty.MethodDelete, "/devices/1", cli.R())
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.IsType(t, Error{}, err, "Error should be rest.Error type")
+	restErr := err.(Error)
+	assert.Equal(t, 1, len(restErr.ApplicationErrors), "Operation application errors should be returned")
+	assert.Equal(t, "IC-NE-01", restErr.ApplicationErrors[0].Code, "Application error code matches")
+}
+
+func TestOperationFollowingTimeout(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+"/devices",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusAccepted, "")
+			resp.Header.Set("Location", "/operations/1")
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/operations/1",
+		httpmock.NewJsonResponderOrPanic(200, map[string]string{"status": "RUNNING"}))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultOperationConfig()
+	conf.Timeout = 20 * time.Millisecond
+	conf.Wait.SetInterval(time.Millisecond, time.Millisecond)
+	cli.SetOperationFollowing(conf)
+	//when
+	_, err := cli.Do(resty.MethodPost, "/devices", cli.R())
+	//then
+	assert.IsType(t, Error{}, err, "Error should be rest.Error type")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error should be classified as context error")
+}
+
+func TestOperationFollowingDisabled(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+"/devices",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusAccepted, "")
+			resp.Header.Set("Location", "/operations/1")
+			return resp, nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	//when
+	resp, err := cli.Do(resty.MethodPost, "/devices", cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode(), "Accepted response should be returned")
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Operation should not be followed")
+}
+
+func TestOperationFollowingDefaults(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodPost, baseURL+"/devices",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusAccepted, "")
+			resp.Header.Set("Operation-Location", "/operations/1")
+			return resp, nil
+		},
+	)
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/operations/1",
+		httpmock.NewJsonResponderOrPanic(200, map[string]string{"status": "SUCCEEDED"}))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetOperationFollowing(&OperationConfig{ErrorsFieldName: "errors"})
+	//when
+	resp, err := cli.Do(resty.MethodPost, "/devices", cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 200, resp.StatusCode(), "Completed operation response should be returned")
+	assert.Equal(t, 2, mock.GetTotalCallCount(), "Operation should be polled with default configuration")
+}
@@ -136,10 +136,11 @@ func (c Client) GetPaginated(path string, result interface{}, conf *PagingConfig
 	if reflect.ValueOf(result).Kind() != reflect.Ptr {
 		return nil, fmt.Errorf("operation failed, provided result is not a ptr")
 	}
+	ctx := c.correlationContext(c.ctx, nil)
 	req := c.R().SetResult(result).
 		SetQueryParams(conf.AdditionalParams).
 		SetQueryParam(conf.SizeParamName, strconv.Itoa(c.PageSize))
-	if err := c.Execute(req, resty.MethodGet, path); err != nil {
+	if _, err := c.do(ctx, resty.MethodGet, path, req); err != nil {
 		return nil, err
 	}
 	totalValue, err := getFieldValueFromStruct(result, conf.TotalCountFieldName, reflect.Int)
@@ -168,7 +169,7 @@ func (c Client) GetPaginated(path string, result interface{}, conf *PagingConfig
 			SetQueryParams(conf.AdditionalParams).
 			SetQueryParam(conf.SizeParamName, strconv.Itoa(c.PageSize)).
 			SetQueryParam(conf.PageParamName, strconv.Itoa(pageNum))
-		if err := c.Execute(req, resty.MethodGet, path); err != nil {
+		if _, err := c.do(ctx, resty.MethodGet, path, req); err != nil {
 			return nil, err
 		}
 		resContent, err := getFieldValueFromStruct(nextResult, conf.ContentFieldName, reflect.Slice)
@@ -191,10 +192,11 @@ func (c Client) GetOffsetPaginated(path string, result interface{}, conf *Offset
 	if reflect.ValueOf(result).Kind() != reflect.Ptr {
 		return nil, fmt.Errorf("operation failed, provided result is not a ptr")
 	}
+	ctx := c.correlationContext(c.ctx, nil)
 	req := c.R().SetResult(result).
 		SetQueryParams(conf.AdditionalParams).
 		SetQueryParam(conf.LimitFieldName, strconv.Itoa(c.PageSize))
-	if err := c.Execute(req, resty.MethodGet, path); err != nil {
+	if _, err := c.do(ctx, resty.MethodGet, path, req); err != nil {
 		return nil, err
 	}
 	paginationData, err := getFieldValueFromStruct(result, conf.PaginationFieldName, reflect.Struct)
@@ -222,7 +224,7 @@ func (c Client) GetOffsetPaginated(path string, result interface{}, conf *Offset
 			SetQueryParams(conf.AdditionalParams).
 			SetQueryParam(conf.LimitFieldName, strconv.Itoa(c.PageSize)).
 			SetQueryParam(conf.OffsetFieldName, strconv.Itoa(offset))
-		if err := c.Execute(req, resty.MethodGet, path); err != nil {
+		if _, err := c.do(ctx, resty.MethodGet, path, req); err != nil {
 			return nil, err
 		}
 		responseData, err := getFieldValueFromStruct(nextResult, conf.DataFieldName, reflect.Slice)
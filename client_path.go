@@ -0,0 +1,91 @@
+package rest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+//ExpandPath expands given path template, like /ne/v1/devices/{uuid}, by replacing
+//each {name} placeholder with a path escaped value from a given params map.
+//Error is returned when template is malformed, when placeholder has no value or when
+//value is a dot segment, . or .., that would change the path
+func ExpandPath(template string, params map[string]string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(template); {
+		switch template[i] {
+		case '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("path template %q has unclosed placeholder at position %d", template, i)
+			}
+			name := template[i+1 : i+end]
+			if name == "" || strings.ContainsAny(name, "{/") {
+				return "", fmt.Errorf("path template %q has invalid placeholder %q", template, template[i:i+end+1])
+			}
+			value, ok := params[name]
+			if !ok {
+				return "", fmt.Errorf("path template %q has no value for placeholder %q", template, name)
+			}
+			if value == "" {
+				return "", fmt.Errorf("path template %q has empty value for placeholder %q", template, name)
+			}
+			if value == "." || value == ".." {
+				return "", fmt.Errorf("path template %q has dot segment value for placeholder %q", template, name)
+			}
+			b.WriteString(url.PathEscape(value))
+			i += end + 1
+		case '}':
+			return "", fmt.Errorf("path template %q has unexpected '}' at position %d", template, i)
+		default:
+			b.WriteByte(template[i])
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+//joinURL joins given base URL, that may include path prefix, with a given path.
+//Path is expected to be already escaped and may include query string
+func joinURL(baseURL string, path string) (string, error) {
+	if strings.TrimSpace(path) == "" {
+		return "", fmt.Errorf("request path is empty")
+	}
+	if strings.HasPrefix(path, "//") || strings.Contains(path, "://") {
+		return "", fmt.Errorf("request path %q is not relative", path)
+	}
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("base URL %q is invalid: %s", baseURL, err)
+	}
+	if !base.IsAbs() || base.Host == "" {
+		return "", fmt.Errorf("base URL %q is not absolute", baseURL)
+	}
+	if base.RawQuery != "" || base.Fragment != "" {
+		return "", fmt.Errorf("base URL %q must not contain query or fragment", baseURL)
+	}
+	return strings.TrimRight(baseURL, "/") + "/" + strings.TrimLeft(path, "/"), nil
+}
+
+//matchPathTemplate reports whether given escaped URL path matches given path template.
+//Template placeholders, like {uuid}, match any single path segment
+func matchPathTemplate(template string, path string) bool {
+	templateSegments := strings.Split(strings.Trim(template, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(templateSegments) != len(pathSegments) {
+		return false
+	}
+	for i := range templateSegments {
+		if strings.HasPrefix(templateSegments[i], "{") && strings.HasSuffix(templateSegments[i], "}") {
+			continue
+		}
+		if templateSegments[i] != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
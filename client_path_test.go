@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandPath(t *testing.T) {
+	//given
+	template := "/ne/v1/devices/{uuid}/acl/{name}"
+	params := map[string]string{
+		"uuid": "a1b2",
+		"name": "my acl/1?",
+	}
+	//when
+	path, err := ExpandPath(template, params)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, "/ne/v1/devices/a1b2/acl/my%20acl%2F1%3F", path, "Path values are escaped")
+}
+
+func TestExpandPathInvalid(t *testing.T) {
+	//given
+	templates := map[string]map[string]string{
+		"/devices/{uuid":        {"uuid": "1"},
+		"/devices/uuid}":        {"uuid": "1"},
+		"/devices/{}":           {"": "1"},
+		"/devices/{uuid}":       {},
+		"/devices/{uuid}/acl":   {"uuid": ""},
+		"/ne/v1/devices/{uuid}": {"uuid": ".."},
+		"/devices/{uuid}/ports": {"uuid": "."},
+	}
+	for template, params := range templates {
+		//when
+		_, err := ExpandPath(template, params)
+		//then
+		assert.NotNilf(t, err, "Error should be returned for template %q", template)
+	}
+}
+
+func TestJoinURL(t *testing.T) {
+	//given
+	cases := []struct {
+		base     string
+		path     string
+		expected string
+	}{
+		{"http://localhost:8888", "/objects", "http://localhost:8888/objects"},
+		{"http://localhost:8888/", "/objects", "http://localhost:8888/objects"},
+		{"http://localhost:8888/", "objects", "http://localhost:8888/objects"},
+		{"http://localhost:8888/api/", "/objects?a=b", "http://localhost:8888/api/objects?a=b"},
+		{"http://localhost:8888/api", "objects/x%2Fy", "http://localhost:8888/api/objects/x%2Fy"},
+	}
+	for _, tc := range cases {
+		//when
+		url, err := joinURL(tc.base, tc.path)
+		//then
+		assert.Nil(t, err, "Error should not be returned")
+		assert.Equal(t, tc.expected, url, "Joined URL matches")
+	}
+}
+
+func TestJoinURLInvalid(t *testing.T) {
+	//given
+	cases := map[string]string{
+		"http://localhost:8888":      "",
+		"http://localhost:8888/api":  "http://other/objects",
+		"localhost:8888":             "/objects",
+		"/api":                       "/objects",
+		"http://localhost:8888/?a=b": "/objects",
+	}
+	for base, path := range cases {
+		//when
+		_, err := joinURL(base, path)
+		//then
+		assert.NotNilf(t, err, "Error should be returned for base %q and path %q", base, path)
+	}
+}
+
+func TestDoEmptyPath(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	//when
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	_, err := cli.Do(resty.MethodGet, "", cli.R())
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.IsType(t, Error{}, err, "Error should be rest.Error type")
+	assert.Equal(t, 0, mock.GetTotalCallCount(), "No HTTP call should be made")
+}
+
+func TestDoEscapedPath(t *testing.T) {
+	//given
+	resourcePath, _ := ExpandPath("/objects/{id}", map[string]string{"id": "a/b"})
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/api/objects/a%2Fb", httpmock.NewStringResponder(200, "{}"))
+	//when
+	cli := NewClient(context.Background(), baseURL+"/api/", &http.Client{Transport: mock})
+	_, err := cli.Do(resty.MethodGet, resourcePath, cli.R())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "One HTTP call should be made")
+}
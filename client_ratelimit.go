@@ -0,0 +1,283 @@
+package rest
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//RateLimitQuota describes rate limit quota reported by server with X-RateLimit-Limit,
+//X-RateLimit-Remaining and X-RateLimit-Reset response headers
+type RateLimitQuota struct {
+	//Limit is a maximum number of requests allowed in current window
+	Limit int
+	//Remaining is a number of requests remaining in current window
+	Remaining int
+	//Reset is a time when current window ends and quota is restored
+	Reset time.Time
+	//UpdatedAt is a time when quota was reported
+	UpdatedAt time.Time
+}
+
+//SetRateLimit sets token bucket rate limit applied to all requests. Requests wait for
+//a token, respecting request context, before being sent. Rate is a number of requests
+//per second and burst is a maximum number of requests sent at once. Rate that is not
+//positive disables global limit
+func (c *Client) SetRateLimit(rate float64, burst int) *Client {
+	limiter := c.limiter()
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.global = newTokenBucket(rate, burst)
+	return c
+}
+
+//SetRouteRateLimit sets token bucket rate limit applied, in addition to global limit,
+//to requests with a given method and URL path matching given path template, like
+///ne/v1/devices/{uuid}. Empty method matches any method. Rate that is not positive
+//removes route limit
+func (c *Client) SetRouteRateLimit(method string, template string, rate float64, burst int) *Client {
+	limiter := c.limiter()
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	method = strings.ToUpper(method)
+	routes := limiter.routes[:0]
+	for _, route := range limiter.routes {
+		if route.method != method || route.template != template {
+			routes = append(routes, route)
+		}
+	}
+	if bucket := newTokenBucket(rate, burst); bucket != nil {
+		routes = append(routes, routeLimit{method: method, template: template, bucket: bucket})
+	}
+	limiter.routes = routes
+	return c
+}
+
+//RateLimitQuota returns rate limit quota reported with the most recent response
+//that carried rate limit headers. False is returned when no quota was reported
+func (c *Client) RateLimitQuota() (RateLimitQuota, bool) {
+	return c.quota.get()
+}
+
+//SetAdaptiveRateLimit enables or disables slowing down requests when remaining quota
+//reported by server drops to a given threshold or below. Remaining requests are then spread
+//evenly until quota reset time and, when quota is exhausted, requests wait for the reset
+func (c *Client) SetAdaptiveRateLimit(enabled bool, threshold int) *Client {
+	c.quota.mu.Lock()
+	defer c.quota.mu.Unlock()
+	c.quota.adaptive = enabled
+	c.quota.threshold = threshold
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type rateLimiter struct {
+	mu     sync.RWMutex
+	global *tokenBucket
+	routes []routeLimit
+}
+
+type routeLimit struct {
+	method   string
+	template string
+	bucket   *tokenBucket
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+//rateLimitHeaders are response headers that report rate limit quota
+var rateLimitHeaders = []string{"X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset"}
+
+func (c *Client) limiter() *rateLimiter {
+	if c.rateLimiter == nil {
+		c.rateLimiter = &rateLimiter{}
+	}
+	return c.rateLimiter
+}
+
+func (l *rateLimiter) wait(ctx context.Context, method string, rawURL string) error {
+	l.mu.RLock()
+	buckets := make([]*tokenBucket, 0, 2)
+	if l.global != nil {
+		buckets = append(buckets, l.global)
+	}
+	if len(l.routes) > 0 {
+		path := rawURL
+		if u, err := url.Parse(rawURL); err == nil {
+			path = u.EscapedPath()
+		}
+		for _, route := range l.routes {
+			if (route.method == "" || route.method == method) && matchPathTemplate(route.template, path) {
+				buckets = append(buckets, route.bucket)
+			}
+		}
+	}
+	l.mu.RUnlock()
+	for i, bucket := range buckets {
+		if err := bucket.wait(ctx); err != nil {
+			for _, taken := range buckets[:i] {
+				taken.refund()
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		now:    time.Now,
+	}
+}
+
+func (b *tokenBucket) wait(ctx context.Context) error {
+	b.mu.Lock()
+	now := b.now()
+	if !b.last.IsZero() {
+		b.tokens += now.Sub(b.last).Seconds() * b.rate
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+	}
+	b.last = now
+	b.tokens--
+	delay := time.Duration(0)
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rate * float64(time.Second))
+	}
+	b.mu.Unlock()
+	if delay == 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		b.refund()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//refund returns token taken by a request that was not sent
+func (b *tokenBucket) refund() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens++
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+}
+
+type quotaTracker struct {
+	mu        sync.RWMutex
+	quota     RateLimitQuota
+	reported  bool
+	adaptive  bool
+	threshold int
+	now       func() time.Time
+}
+
+func newQuotaTracker() *quotaTracker {
+	return &quotaTracker{now: time.Now}
+}
+
+func (t *quotaTracker) get() (RateLimitQuota, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.quota, t.reported
+}
+
+func (t *quotaTracker) capture(resp *resty.Response) {
+	if resp == nil || resp.RawResponse == nil {
+		return
+	}
+	header := resp.Header()
+	remaining, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Remaining")))
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	quota := RateLimitQuota{Remaining: remaining, UpdatedAt: now}
+	if limit, err := strconv.Atoi(strings.TrimSpace(header.Get("X-RateLimit-Limit"))); err == nil {
+		quota.Limit = limit
+	}
+	if reset, ok := parseRateLimitReset(header.Get("X-RateLimit-Reset"), now); ok {
+		quota.Reset = reset
+	}
+	t.quota = quota
+	t.reported = true
+}
+
+func (t *quotaTracker) delay() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.adaptive || !t.reported || t.quota.Reset.IsZero() || t.quota.Remaining > t.threshold {
+		return 0
+	}
+	untilReset := t.quota.Reset.Sub(t.now())
+	if untilReset <= 0 {
+		return 0
+	}
+	if t.quota.Remaining <= 0 {
+		return untilReset
+	}
+	delay := untilReset / time.Duration(t.quota.Remaining+1)
+	t.quota.Remaining--
+	return delay
+}
+
+func (t *quotaTracker) wait(ctx context.Context) error {
+	delay := t.delay()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+//parseRateLimitReset parses X-RateLimit-Reset header value that is either a number
+//of seconds until reset or, for large values, Unix time of the reset
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+	if seconds >= 1e9 {
+		return time.Unix(int64(seconds), 0), true
+	}
+	return now.Add(time.Duration(seconds * float64(time.Second))), true
+}
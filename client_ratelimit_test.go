@@ -0,0 +1,179 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	//given
+	resourcePath := "/devices"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath, httpmock.NewStringResponder(http.StatusOK, ""))
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetRateLimit(20, 1)
+	//when
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := cli.Execute(cli.R(), resty.MethodGet, resourcePath); err != nil {
+			assert.Failf(t, "request failed", "%s", err)
+		}
+	}
+	elapsed := time.Since(start)
+	//then
+	assert.Equal(t, 3, mock.GetTotalCallCount(), "All requests should be sent")
+	assert.GreaterOrEqual(t, int64(elapsed), int64(90*time.Millisecond), "Requests should be paced")
+}
+
+func TestRouteRateLimit(t *testing.T) {
+	//given
+	devicePath := "/ne/v1/devices/{uuid}"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(httpmock.NewStringResponder(http.StatusOK, ""))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	cli := NewClient(ctx, baseURL, &http.Client{Transport: mock})
+	cli.SetRouteRateLimit(resty.MethodPost, devicePath, 0.001, 1)
+	//when
+	firstErr := cli.Execute(cli.R(), resty.MethodPost, "/ne/v1/devices/abc")
+	getErr := cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices/abc")
+	limitedErr := cli.Execute(cli.R(), resty.MethodPost, "/ne/v1/devices/def")
+	//then
+	assert.Nil(t, firstErr, "First request should be sent within burst")
+	assert.NotNil(t, limitedErr, "Limited request should fail when context is done")
+	assert.IsType(t, Error{}, limitedErr, "Error should be rest.Error type")
+	assert.True(t, errors.Is(limitedErr, context.DeadlineExceeded), "Error should be classified as context error")
+	assert.Nil(t, getErr, "Request for other method should not be limited")
+	assert.Equal(t, 2, mock.GetTotalCallCount(), "Limited request should not be sent")
+}
+
+func TestTokenBucketCancelReturnsToken(t *testing.T) {
+	//given
+	bucket := newTokenBucket(1, 1)
+	now := time.Now()
+	bucket.now = func() time.Time { return now }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	//when
+	firstErr := bucket.wait(ctx)
+	canceledErr := bucket.wait(ctx)
+	now = now.Add(time.Second)
+	refilledErr := bucket.wait(ctx)
+	//then
+	assert.Nil(t, firstErr, "First token should be available")
+	assert.Equal(t, context.Canceled, canceledErr, "Canceled wait should fail")
+	assert.Nil(t, refilledErr, "Canceled wait should return token")
+}
+
+func TestRateLimitQuota(t *testing.T) {
+	//given
+	resourcePath := "/devices"
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusTooManyRequests, "")
+			resp.Header.Set("X-RateLimit-Limit", "100")
+			resp.Header.Set("X-RateLimit-Remaining", "0")
+			resp.Header.Set("X-RateLimit-Reset", "30")
+			return resp, nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	now := time.Now()
+	cli.quota.now = func() time.Time { return now }
+	//when
+	_, reportedBefore := cli.RateLimitQuota()
+	cli.Execute(cli.R(), resty.MethodGet, resourcePath)
+	quota, reported := cli.RateLimitQuota()
+	//then
+	assert.False(t, reportedBefore, "Quota should not be reported before response")
+	assert.True(t, reported, "Quota should be reported")
+	assert.Equal(t, 100, quota.Limit, "Quota limit matches")
+	assert.Equal(t, 0, quota.Remaining, "Quota remaining matches")
+	assert.Equal(t, now.Add(30*time.Second), quota.Reset, "Quota reset matches")
+}
+
+func TestAdaptiveRateLimit(t *testing.T) {
+	//given
+	tracker := newQuotaTracker()
+	now := time.Now()
+	tracker.now = func() time.Time { return now }
+	tracker.adaptive = true
+	tracker.threshold = 3
+	reset := now.Add(8 * time.Second)
+	//when
+	tracker.quota = RateLimitQuota{Remaining: 10, Reset: reset}
+	tracker.reported = true
+	aboveThresholdDelay := tracker.delay()
+	tracker.quota = RateLimitQuota{Remaining: 3, Reset: reset}
+	belowThresholdDelay := tracker.delay()
+	tracker.quota = RateLimitQuota{Remaining: 0, Reset: reset}
+	exhaustedDelay := tracker.delay()
+	//then
+	assert.Equal(t, time.Duration(0), aboveThresholdDelay, "Request above threshold should not be delayed")
+	assert.Equal(t, 2*time.Second, belowThresholdDelay, "Remaining requests should be spread until reset")
+	assert.Equal(t, 8*time.Second, exhaustedDelay, "Request should wait for reset when quota is exhausted")
+}
+
+func TestParseRateLimitReset(t *testing.T) {
+	//given
+	now := time.Unix(1600000000, 0)
+	//when
+	delta, deltaOk := parseRateLimitReset("60", now)
+	epoch, epochOk := parseRateLimitReset("1600000120", now)
+	_, invalidOk := parseRateLimitReset("soon", now)
+	//then
+	assert.True(t, deltaOk, "Delta seconds should be parsed")
+	assert.Equal(t, now.Add(time.Minute), delta, "Delta seconds should be added to current time")
+	assert.True(t, epochOk, "Unix time should be parsed")
+	assert.Equal(t, now.Add(2*time.Minute), epoch, "Unix time matches")
+	assert.False(t, invalidOk, "Invalid value should not be parsed")
+}
+
+func TestRateLimitRefundsTakenTokens(t *testing.T) {
+	//given
+	cli := NewClient(context.Background(), baseURL, &http.Client{})
+	cli.SetRateLimit(0.001, 1).SetRouteRateLimit(resty.MethodPost, "/devices", 0.001, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	cli.rateLimiter.routes[0].bucket.tokens = 0
+	//when
+	limitedErr := cli.rateLimiter.wait(ctx, resty.MethodPost, baseURL+"/devices")
+	//then
+	assert.True(t, errors.Is(limitedErr, context.Canceled), "Limited request should fail with context error")
+	assert.InDelta(t, 1, cli.rateLimiter.global.tokens, 0.01, "Global token should be refunded")
+}
+
+func TestRateLimitQuotaIgnoresCachedResponses(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		resp := httpmock.NewStringResponse(http.StatusOK, "{}")
+		resp.Header.Set("Cache-Control", "max-age=60")
+		resp.Header.Set("X-RateLimit-Limit", "100")
+		resp.Header.Set("X-RateLimit-Remaining", "0")
+		resp.Header.Set("X-RateLimit-Reset", "2")
+		return resp, nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetCache(NewMemoryCacheStorage(10))
+	now := time.Now()
+	cli.quota.now = func() time.Time { return now }
+	cli.Execute(cli.R(), resty.MethodGet, "/devices")
+	now = now.Add(time.Second)
+	//when
+	err := cli.Execute(cli.R(), resty.MethodGet, "/devices")
+	quota, _ := cli.RateLimitQuota()
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Second response should be served from cache")
+	assert.Equal(t, now.Add(-time.Second), quota.UpdatedAt, "Cached response should not update quota")
+	assert.Equal(t, now.Add(time.Second), quota.Reset, "Quota reset should not be moved by cached response")
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//SetSingleFlight enables or disables coalescing of identical GET requests. GET request
+//issued while identical one, with the same URL, query and authorization, is in flight
+//does not reach the server. Instead it waits for the in-flight request and receives
+//its response, with the body decoded into request's result. Error of the in-flight
+//request is returned to all waiting requests. Request that is cancelled or times out
+//stops waiting alone, in-flight request is cancelled only when no request waits for it
+func (c *Client) SetSingleFlight(enabled bool) *Client {
+	if !enabled {
+		c.flights = nil
+		return c
+	}
+	if c.flights == nil {
+		c.flights = &flightGroup{calls: make(map[string]*flightCall)}
+	}
+	return c
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	done    chan struct{}
+	resp    *resty.Response
+	err     error
+	callers int
+	cancel  context.CancelFunc
+}
+
+//do executes given function unless call with the same key is in flight, in which case
+//it joins that call. Function runs with context that carries values of the first caller's context
+//but is cancelled only when all callers stop waiting. Caller whose context is done stops
+//waiting with context error while the call continues for other callers
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (*resty.Response, error)) (*resty.Response, error) {
+	g.mu.Lock()
+	call, ok := g.calls[key]
+	if ok {
+		call.callers++
+	} else {
+		callCtx, cancel := context.WithCancel(detachedContext{ctx})
+		call = &flightCall{done: make(chan struct{}), callers: 1, cancel: cancel}
+		g.calls[key] = call
+		go func() {
+			call.resp, call.err = fn(callCtx)
+			g.mu.Lock()
+			g.remove(key, call)
+			g.mu.Unlock()
+			cancel()
+			close(call.done)
+		}()
+	}
+	g.mu.Unlock()
+	select {
+	case <-call.done:
+		return call.resp, call.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		call.callers--
+		if call.callers == 0 {
+			g.remove(key, call)
+			call.cancel()
+		}
+		g.mu.Unlock()
+		return nil, Error{Message: "waiting for in-flight request failed: " + ctx.Err().Error(), cause: ctx.Err()}
+	}
+}
+
+//remove removes given call from in-flight calls unless other call with the same key replaced it
+func (g *flightGroup) remove(key string, call *flightCall) {
+	if g.calls[key] == call {
+		delete(g.calls, key)
+	}
+}
+
+//executeShared executes GET request coalesced with identical in-flight requests. Shared request
+//is a copy of the first caller's request and every caller decodes response into its own result
+func (c *Client) executeShared(ctx context.Context, url string, req *resty.Request) (*resty.Response, error) {
+	sharedReq := *req
+	sharedReq.Header = req.Header.Clone()
+	sharedReq.Result = nil
+	resp, err := c.flights.do(ctx, c.flightKey(url, req), func(callCtx context.Context) (*resty.Response, error) {
+		return c.execute(callCtx, resty.MethodGet, url, &sharedReq)
+	})
+	if err != nil || resp == nil || req.Result == nil || len(resp.Body()) == 0 {
+		return resp, err
+	}
+	if err := json.Unmarshal(resp.Body(), req.Result); err != nil {
+		return resp, Error{Message: "decoding shared response failed: " + err.Error(), HTTPCode: resp.StatusCode(), cause: err}
+	}
+	return resp, nil
+}
+
+func (c *Client) flightKey(url string, req *resty.Request) string {
+	key := url
+	if query := req.QueryParam.Encode(); query != "" {
+		key += "?" + query
+	}
+	identity := req.Header.Get("Authorization")
+	if identity == "" {
+		identity = c.Header.Get("Authorization")
+	}
+	if req.Token != "" {
+		identity += "|" + req.Token
+	} else if c.Token != "" {
+		identity += "|" + c.Token
+	}
+	if req.UserInfo != nil {
+		identity += "|" + req.UserInfo.Username + ":" + req.UserInfo.Password
+	} else if c.UserInfo != nil {
+		identity += "|" + c.UserInfo.Username + ":" + c.UserInfo.Password
+	}
+	if identity != "" {
+		sum := sha256.Sum256([]byte(identity))
+		key += "#" + hex.EncodeToString(sum[:])
+	}
+	return key
+}
+
+//detachedContext carries values of a parent context but is not cancelled with it
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
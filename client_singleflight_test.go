@@ -0,0 +1,115 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSingleFlight(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			started <- struct{}{}
+			<-release
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]string{"uuid": "1", "status": "PROVISIONED"})
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetSingleFlight(true)
+	waiters := 3
+	results := make([]TestDevice, waiters+1)
+	errs := make([]error, waiters+1)
+	//when
+	var wg sync.WaitGroup
+	get := func(i int) {
+		defer wg.Done()
+		errs[i] = cli.Execute(cli.R().SetResult(&results[i]), resty.MethodGet, resourcePath)
+	}
+	wg.Add(1)
+	go get(0)
+	<-started
+	for i := 1; i <= waiters; i++ {
+		wg.Add(1)
+		go get(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	//then
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Identical requests should be coalesced")
+	for i := range results {
+		assert.Nil(t, errs[i], "Error should not be returned")
+		if assert.NotNil(t, results[i].Status, "Result should be decoded") {
+			assert.Equal(t, "PROVISIONED", *results[i].Status, "Result matches")
+		}
+	}
+}
+
+func TestSingleFlightKey(t *testing.T) {
+	//given
+	cli := NewClient(context.Background(), baseURL, &http.Client{})
+	url := baseURL + "/devices"
+	//when
+	key := cli.flightKey(url, cli.R().SetQueryParam("page", "1").SetAuthToken("a"))
+	sameKey := cli.flightKey(url, cli.R().SetQueryParam("page", "1").SetAuthToken("a"))
+	otherQueryKey := cli.flightKey(url, cli.R().SetQueryParam("page", "2").SetAuthToken("a"))
+	otherAuthKey := cli.flightKey(url, cli.R().SetQueryParam("page", "1").SetAuthToken("b"))
+	//then
+	assert.Equal(t, key, sameKey, "Identical requests should have the same key")
+	assert.NotEqual(t, key, otherQueryKey, "Requests with different query should have different keys")
+	assert.NotEqual(t, key, otherAuthKey, "Requests with different authorization should have different keys")
+}
+
+func TestSingleFlightLeaderCancelled(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			started <- struct{}{}
+			<-release
+			return httpmock.NewJsonResponse(http.StatusOK, map[string]string{"uuid": "1", "status": "PROVISIONED"})
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetSingleFlight(true)
+	leaderCtx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := cli.do(leaderCtx, resty.MethodGet, resourcePath, cli.R())
+		leaderErr <- err
+	}()
+	<-started
+	waiterErr := make(chan error, 1)
+	result := TestDevice{}
+	go func() {
+		waiterErr <- cli.Execute(cli.R().SetResult(&result), resty.MethodGet, resourcePath)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	//when
+	cancel()
+	cancelErr := <-leaderErr
+	close(release)
+	err := <-waiterErr
+	//then
+	assert.True(t, errors.Is(cancelErr, context.Canceled), "Cancelled leader should fail with context error")
+	assert.Nil(t, err, "Waiter should not fail when leader is cancelled")
+	if assert.NotNil(t, result.Status, "Result should be decoded") {
+		assert.Equal(t, "PROVISIONED", *result.Status, "Result matches")
+	}
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Identical requests should be coalesced")
+}
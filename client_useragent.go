@@ -0,0 +1,68 @@
+package rest
+
+import (
+	"runtime"
+	"strings"
+)
+
+//AddUserAgentProduct appends product token with a given name and version, like ne-go and 2.1.0,
+//to client's User-Agent header, i.e. equinix-rest-go/1.4.0 (ne-go/2.1.0). Products are listed in
+//order they were added, adding product with the same name again replaces its version.
+//Characters not allowed in User-Agent tokens are replaced with dashes
+func (c *Client) AddUserAgentProduct(name string, version string) *Client {
+	name = userAgentToken(name)
+	version = userAgentToken(version)
+	token := name + "/" + version
+	for i := range c.userAgentProducts {
+		if strings.HasPrefix(c.userAgentProducts[i], name+"/") {
+			c.userAgentProducts[i] = token
+			return c.applyUserAgent()
+		}
+	}
+	c.userAgentProducts = append(c.userAgentProducts, token)
+	return c.applyUserAgent()
+}
+
+//SetUserAgentPlatform enables or disables appending Go version, operating system
+//and architecture, like Go/1.15.2 (linux; amd64), to client's User-Agent header
+func (c *Client) SetUserAgentPlatform(enabled bool) *Client {
+	c.userAgentPlatform = enabled
+	return c.applyUserAgent()
+}
+
+//UserAgent returns User-Agent header sent by the client
+func (c *Client) UserAgent() string {
+	var b strings.Builder
+	b.WriteString("equinix-rest-go/" + Version)
+	if len(c.userAgentProducts) > 0 {
+		b.WriteString(" (" + strings.Join(c.userAgentProducts, "; ") + ")")
+	}
+	if c.userAgentPlatform {
+		goVersion := userAgentToken(strings.TrimPrefix(runtime.Version(), "go"))
+		b.WriteString(" Go/" + goVersion + " (" + runtime.GOOS + "; " + runtime.GOARCH + ")")
+	}
+	return b.String()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (c *Client) applyUserAgent() *Client {
+	c.SetHeader("User-Agent", c.UserAgent())
+	return c
+}
+
+//userAgentToken replaces characters that are not allowed in HTTP tokens
+//and characters that delimit User-Agent product comments with dashes
+func userAgentToken(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`()<>@,;:\"/[]?={}`, r) {
+			return '-'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserAgent(t *testing.T) {
+	//given
+	var header string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		header = r.Header.Get("User-Agent")
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	//when
+	defaultUserAgent := cli.UserAgent()
+	cli.AddUserAgentProduct("ne-go", "2.0.0").
+		AddUserAgentProduct("terraform-provider-equinix", "1.5.0").
+		AddUserAgentProduct("ne-go", "2.1.0")
+	err := cli.Execute(cli.R(), resty.MethodGet, "/objects")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, "equinix-rest-go/"+Version, defaultUserAgent, "Default User-Agent matches")
+	assert.Equal(t, "equinix-rest-go/"+Version+" (ne-go/2.1.0; terraform-provider-equinix/1.5.0)", header, "User-Agent header matches")
+}
+
+func TestUserAgentPlatform(t *testing.T) {
+	//given
+	cli := NewClient(context.Background(), baseURL, &http.Client{})
+	//when
+	cli.SetUserAgentPlatform(true)
+	//then
+	goVersion := strings.TrimPrefix(runtime.Version(), "go")
+	assert.True(t, strings.HasSuffix(cli.UserAgent(), " ("+runtime.GOOS+"; "+runtime.GOARCH+")"), "Platform should be appended")
+	assert.Contains(t, cli.UserAgent(), " Go/"+userAgentToken(goVersion), "Go version should be appended")
+	assert.Equal(t, cli.UserAgent(), cli.Header.Get("User-Agent"), "User-Agent header should be updated")
+}
+
+func TestUserAgentToken(t *testing.T) {
+	//given
+	values := map[string]string{
+		"ne-go":          "ne-go",
+		"my product":     "my-product",
+		"a/b;c(d)":       "a-b-c-d-",
+		"":               "unknown",
+		"1.0.0+build.12": "1.0.0+build.12",
+	}
+	for value, expected := range values {
+		//when
+		token := userAgentToken(value)
+		//then
+		assert.Equalf(t, expected, token, "Token for %q matches", value)
+	}
+}
@@ -0,0 +1,163 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//ErrUnsupportedVersion is matched by errors.Is for Error returned when server reports
+//that API version declared for a route group is not supported
+var ErrUnsupportedVersion = errors.New("unsupported API version")
+
+//SetAPIVersion declares API version, like v1, for a route group with a given path prefix,
+//like /ne. Version is injected as path segment following the prefix, i.e. request for
+///ne/devices is sent to /ne/v1/devices. Version is injected into requests, polls of WaitFor
+//and followed operations and into paths given to ETag and SetIfMatch. Paths that already carry declared version are left
+//intact and paths that carry other version fail. Server's 406 Not Acceptable response
+//fails with Error matching ErrUnsupportedVersion
+func (c *Client) SetAPIVersion(prefix string, version string) *Client {
+	return c.addAPIVersion(apiVersion{prefix: normalizePrefix(prefix), version: version})
+}
+
+//SetAPIVersionHeader declares API version for a route group with a given path prefix,
+//sent in a header with a given name. Empty prefix declares version for all requests.
+//Server's 406 Not Acceptable response, or response carrying the same header with other
+//version, fails with Error matching ErrUnsupportedVersion
+func (c *Client) SetAPIVersionHeader(prefix string, header string, version string) *Client {
+	return c.addAPIVersion(apiVersion{prefix: normalizePrefix(prefix), version: version, header: header})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+var versionSegmentRegexp = regexp.MustCompile(`^v[0-9]+(\.[0-9]+)*(alpha[0-9]*|beta[0-9]*)?$`)
+
+type apiVersion struct {
+	prefix  string
+	version string
+	header  string
+}
+
+func (c *Client) addAPIVersion(v apiVersion) *Client {
+	versions := make([]apiVersion, 0, len(c.apiVersions)+1)
+	for _, existing := range c.apiVersions {
+		if existing.prefix != v.prefix {
+			versions = append(versions, existing)
+		}
+	}
+	c.apiVersions = append(versions, v)
+	return c
+}
+
+//apiVersion returns version declared for the route group with the longest prefix matching given path
+func (c *Client) apiVersion(path string) (apiVersion, bool) {
+	var match apiVersion
+	found := false
+	for _, v := range c.apiVersions {
+		if hasPathPrefix(path, v.prefix) && (!found || len(v.prefix) > len(match.prefix)) {
+			match, found = v, true
+		}
+	}
+	return match, found
+}
+
+//versionURL injects API version declared for a path of a given URL, relative to client's
+//base URL, into the URL or into given request's header. Declared version, if any, is returned
+//to check the response
+func (c *Client) versionURL(rawURL string, req *resty.Request) (string, *apiVersion, error) {
+	base := strings.TrimRight(c.baseURL, "/")
+	if !strings.HasPrefix(rawURL, base+"/") {
+		return rawURL, nil, nil
+	}
+	path := strings.TrimPrefix(rawURL, base)
+	version, ok := c.apiVersion(path)
+	if !ok {
+		return rawURL, nil, nil
+	}
+	versionedPath, err := version.apply(path, req)
+	if err != nil {
+		return "", nil, Error{Message: "invalid request path: " + err.Error()}
+	}
+	return base + versionedPath, &version, nil
+}
+
+//apply injects declared API version into given path or request header
+//and returns resulting path
+func (v *apiVersion) apply(path string, req *resty.Request) (string, error) {
+	if v.header != "" {
+		if req != nil {
+			req.SetHeader(v.header, v.version)
+		}
+		return path, nil
+	}
+	query := ""
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path, query = path[:i], path[i:]
+	}
+	rest := strings.TrimPrefix(strings.TrimLeft(path, "/"), strings.TrimLeft(v.prefix, "/"))
+	rest = strings.TrimLeft(rest, "/")
+	segment := rest
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		segment = rest[:i]
+	}
+	if segment == v.version {
+		return path + query, nil
+	}
+	if versionSegmentRegexp.MatchString(segment) {
+		return "", fmt.Errorf("request path %q has API version %q while %q is declared for %q", path, segment, v.version, v.prefix)
+	}
+	versioned := v.prefix + "/" + v.version
+	if rest != "" {
+		versioned += "/" + rest
+	}
+	return versioned + query, nil
+}
+
+//check turns response that reports unsupported version into an error
+func (v *apiVersion) check(resp *resty.Response, err error) error {
+	if v == nil || resp == nil || resp.RawResponse == nil {
+		return err
+	}
+	reported := ""
+	if v.header != "" {
+		reported = resp.Header().Get(v.header)
+	}
+	if resp.StatusCode() != http.StatusNotAcceptable && (reported == "" || reported == v.version) {
+		return err
+	}
+	restErr := Error{}
+	if !errors.As(err, &restErr) {
+		restErr = Error{HTTPCode: resp.StatusCode()}
+	}
+	restErr.Message = fmt.Sprintf("API version %q declared for %q is not supported", v.version, v.prefix)
+	if reported != "" && reported != v.version {
+		restErr.Message += fmt.Sprintf(", server responded with version %q", reported)
+	}
+	restErr.cause = ErrUnsupportedVersion
+	return restErr
+}
+
+func normalizePrefix(prefix string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return ""
+	}
+	return "/" + prefix
+}
+
+func hasPathPrefix(path string, prefix string) bool {
+	if prefix == "" {
+		return true
+	}
+	path = "/" + strings.TrimLeft(path, "/")
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIVersionPath(t *testing.T) {
+	//given
+	var paths []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		paths = append(paths, r.URL.RequestURI())
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetAPIVersion("/ne", "v1").SetAPIVersion("fabric/", "v4")
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, "/ne/devices?size=10")
+	cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices/1")
+	cli.Execute(cli.R(), resty.MethodGet, "/fabric/connections")
+	cli.Execute(cli.R(), resty.MethodGet, "/network/devices")
+	conflictErr := cli.Execute(cli.R(), resty.MethodGet, "/ne/v2/devices")
+	//then
+	assert.Equal(t, []string{"/ne/v1/devices?size=10", "/ne/v1/devices/1", "/fabric/v4/connections", "/network/devices"},
+		paths, "Version should be injected into paths of route groups")
+	assert.NotNil(t, conflictErr, "Path with other version should fail")
+	assert.Contains(t, conflictErr.Error(), `API version \"v2\" while \"v1\" is declared`, "Error should describe conflict")
+}
+
+func TestAPIVersionHeader(t *testing.T) {
+	//given
+	var headers []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		headers = append(headers, r.Header.Get("Api-Version"))
+		return httpmock.NewStringResponse(http.StatusOK, "{}"), nil
+	})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetAPIVersionHeader("", "Api-Version", "2020-01-01").
+		SetAPIVersionHeader("/fabric/v4", "Api-Version", "2021-06-01")
+	//when
+	cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices")
+	cli.Execute(cli.R(), resty.MethodGet, "/fabric/v4/connections")
+	//then
+	assert.Equal(t, []string{"2020-01-01", "2021-06-01"}, headers, "Version header of the most specific route group should be sent")
+}
+
+func TestAPIVersionUnsupported(t *testing.T) {
+	//given
+	mock := httpmock.NewMockTransport()
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/ne/v9/devices",
+		httpmock.NewStringResponder(http.StatusNotAcceptable, `{"errorCode":"EQ-406","errorMessage":"version not supported"}`))
+	mock.RegisterResponder(resty.MethodGet, baseURL+"/fabric/connections",
+		func(r *http.Request) (*http.Response, error) {
+			resp := httpmock.NewStringResponse(http.StatusOK, "{}")
+			resp.Header.Set("Api-Version", "2019-01-01")
+			return resp, nil
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetAPIVersion("/ne", "v9").SetAPIVersionHeader("/fabric", "Api-Version", "2021-06-01")
+	//when
+	notAcceptableErr := cli.Execute(cli.R(), resty.MethodGet, "/ne/devices")
+	mismatchErr := cli.Execute(cli.R(), resty.MethodGet, "/fabric/connections")
+	//then
+	assert.True(t, errors.Is(notAcceptableErr, ErrUnsupportedVersion), "Not acceptable error should be classified as unsupported version")
+	assert.IsType(t, Error{}, notAcceptableErr, "Error should be rest.Error type")
+	restErr := notAcceptableErr.(Error)
+	assert.Equal(t, http.StatusNotAcceptable, restErr.HTTPCode, "HTTP code matches")
+	assert.Equal(t, "EQ-406", restErr.ApplicationErrors[0].Code, "Application errors should be kept")
+	assert.Contains(t, restErr.Message, `API version "v9" declared for "/ne" is not supported`, "Error message matches")
+	assert.True(t, errors.Is(mismatchErr, ErrUnsupportedVersion), "Version mismatch should be classified as unsupported version")
+	assert.Contains(t, mismatchErr.Error(), `server responded with version \"2019-01-01\"`, "Error should name reported version")
+}
+
+func TestAPIVersionConsistency(t *testing.T) {
+	//given
+	var paths []string
+	mock := httpmock.NewMockTransport()
+	mock.RegisterNoResponder(func(r *http.Request) (*http.Response, error) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		resp := httpmock.NewStringResponse(http.StatusOK, `{"Status":"PROVISIONED"}`)
+		resp.Header.Set("Content-Type", "application/json")
+		resp.Header.Set("ETag", `"v1"`)
+		return resp, nil
+	})
+	sink := &testAuditSink{}
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	cli.SetAPIVersion("/ne", "v1").SetAuditSink(sink)
+	result := make(map[string]interface{})
+	//when
+	waitErr := cli.WaitFor(context.Background(), "/ne/devices/1", &result,
+		DefaultWaitConfig().SetSuccessStatuses("PROVISIONED"))
+	etag, etagOK := cli.ETag("/ne/devices/1")
+	cli.Execute(cli.R(), resty.MethodDelete, "/ne/devices/1")
+	//then
+	assert.Nil(t, waitErr, "Error should not be returned")
+	assert.Equal(t, []string{"GET /ne/v1/devices/1", "DELETE /ne/v1/devices/1"}, paths, "Version should be injected into polled path")
+	assert.True(t, etagOK, "ETag should be found for unversioned path")
+	assert.Equal(t, `"v1"`, etag, "ETag matches")
+	if assert.Len(t, sink.events, 1, "Audit event should be recorded") {
+		assert.Equal(t, "/ne/devices/1", sink.events[0].Path, "Audit path should be given path")
+	}
+}
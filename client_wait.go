@@ -0,0 +1,246 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+//ErrFailureStatus is matched by errors.Is for WaitError returned when
+//resource reached one of configured failure statuses
+var ErrFailureStatus = errors.New("resource reached failure status")
+
+//ErrUnexpectedStatus is matched by errors.Is for WaitError returned when
+//resource reached status that is neither success, failure nor pending one
+var ErrUnexpectedStatus = errors.New("resource reached unexpected status")
+
+//WaitConfig is used to describe how WaitFor polls resource status
+type WaitConfig struct {
+	//StatusFieldName is a name of a field in a response struct that holds resource status
+	StatusFieldName string
+	//StatusFunc extracts status from a response struct. When set, it takes precedence over StatusFieldName
+	StatusFunc func(result interface{}) (string, error)
+	//SuccessStatuses is a list of statuses that finish waiting successfully
+	SuccessStatuses []string
+	//FailureStatuses is a list of statuses that finish waiting with an error
+	FailureStatuses []string
+	//PendingStatuses is a list of statuses that continue waiting. When empty,
+	//any status that is not success or failure one continues waiting
+	PendingStatuses []string
+	//InitialInterval is a delay before second poll. Non-positive value is replaced with default one
+	InitialInterval time.Duration
+	//MaxInterval is a maximum delay between polls, zero means no maximum
+	MaxInterval time.Duration
+	//Multiplier is a factor by which delay grows after each poll. Value lower than one
+	//is replaced with default one
+	Multiplier float64
+}
+
+//WaitError describes unsuccessful outcome of WaitFor operation
+type WaitError struct {
+	//Path is a path of polled resource
+	Path string
+	//LastStatus is the last status of a resource observed before waiting finished
+	LastStatus string
+	//Err is a cause of an error: ErrFailureStatus, ErrUnexpectedStatus,
+	//context error or Error returned by a poll request
+	Err error
+}
+
+func (e WaitError) Error() string {
+	return fmt.Sprintf("waiting for resource %q failed, last status: %q: %s", e.Path, e.LastStatus, e.Err)
+}
+
+//Unwrap returns cause of an error
+func (e WaitError) Unwrap() error {
+	return e.Err
+}
+
+//DefaultWaitConfig returns WaitConfig with default values
+func DefaultWaitConfig() *WaitConfig {
+	return &WaitConfig{
+		StatusFieldName: "Status",
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     time.Minute,
+		Multiplier:      1.5,
+	}
+}
+
+//SetStatusFieldName sets status field name
+func (c *WaitConfig) SetStatusFieldName(v string) *WaitConfig {
+	c.StatusFieldName = v
+	return c
+}
+
+//SetStatusFunc sets function that extracts status from a response struct
+func (c *WaitConfig) SetStatusFunc(v func(result interface{}) (string, error)) *WaitConfig {
+	c.StatusFunc = v
+	return c
+}
+
+//SetSuccessStatuses sets statuses that finish waiting successfully
+func (c *WaitConfig) SetSuccessStatuses(v ...string) *WaitConfig {
+	c.SuccessStatuses = v
+	return c
+}
+
+//SetFailureStatuses sets statuses that finish waiting with an error
+func (c *WaitConfig) SetFailureStatuses(v ...string) *WaitConfig {
+	c.FailureStatuses = v
+	return c
+}
+
+//SetPendingStatuses sets statuses that continue waiting
+func (c *WaitConfig) SetPendingStatuses(v ...string) *WaitConfig {
+	c.PendingStatuses = v
+	return c
+}
+
+//SetInterval sets initial and maximum delay between polls
+func (c *WaitConfig) SetInterval(initial time.Duration, max time.Duration) *WaitConfig {
+	c.InitialInterval = initial
+	c.MaxInterval = max
+	return c
+}
+
+//SetMultiplier sets factor by which delay grows after each poll
+func (c *WaitConfig) SetMultiplier(v float64) *WaitConfig {
+	c.Multiplier = v
+	return c
+}
+
+//WaitFor uses HTTP GET requests to poll resource on a given path until its status
+//reaches one of success or failure statuses. Last response is decoded into given result.
+//Polls failed with 429 Too Many Requests or 5xx responses are retried. Polling stops with
+//an error when given context is done, use context.WithTimeout to limit waiting time. Polling aspects are controlled with WaitConfig, which is required
+func (c *Client) WaitFor(ctx context.Context, path string, result interface{}, conf *WaitConfig) error {
+	if conf == nil {
+		return fmt.Errorf("operation failed, wait configuration is required")
+	}
+	url, err := joinURL(c.baseURL, path)
+	if err != nil {
+		return Error{Message: "invalid request URL: " + err.Error()}
+	}
+	_, err = c.poll(c.correlationContext(ctx, nil), url, result, conf)
+	if waitErr, ok := err.(WaitError); ok {
+		waitErr.Path = path
+		return waitErr
+	}
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (c *Client) poll(ctx context.Context, url string, result interface{}, conf *WaitConfig) (*resty.Response, error) {
+	resultValue := reflect.ValueOf(result)
+	if resultValue.Kind() != reflect.Ptr || resultValue.IsNil() {
+		return nil, fmt.Errorf("operation failed, provided result is not a ptr")
+	}
+	if len(conf.SuccessStatuses) == 0 {
+		return nil, fmt.Errorf("operation failed, no success statuses configured")
+	}
+	conf = conf.withDefaults()
+	interval := conf.InitialInterval
+	status := ""
+	for {
+		resultValue.Elem().Set(reflect.Zero(resultValue.Elem().Type()))
+		req := c.R().SetResult(result)
+		pollURL, version, err := c.versionURL(url, req)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := c.execute(ctx, resty.MethodGet, pollURL, req)
+		if err = version.check(resp, err); err != nil {
+			if !isTransientPollError(resp, err) {
+				return resp, WaitError{Path: url, LastStatus: status, Err: err}
+			}
+		} else {
+			if status, err = conf.status(result); err != nil {
+				return resp, err
+			}
+			switch {
+			case containsString(conf.SuccessStatuses, status):
+				return resp, nil
+			case containsString(conf.FailureStatuses, status):
+				return resp, WaitError{Path: url, LastStatus: status, Err: ErrFailureStatus}
+			case len(conf.PendingStatuses) > 0 && !containsString(conf.PendingStatuses, status):
+				return resp, WaitError{Path: url, LastStatus: status, Err: ErrUnexpectedStatus}
+			}
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, WaitError{Path: url, LastStatus: status, Err: ctx.Err()}
+		case <-timer.C:
+		}
+		interval = conf.nextInterval(interval)
+	}
+}
+
+func (c *WaitConfig) status(result interface{}) (string, error) {
+	if c.StatusFunc != nil {
+		return c.StatusFunc(result)
+	}
+	if resultMap, ok := result.(*map[string]interface{}); ok {
+		status, ok := (*resultMap)[c.StatusFieldName].(string)
+		if !ok {
+			return "", fmt.Errorf("%s field in target map is not a string", c.StatusFieldName)
+		}
+		return status, nil
+	}
+	statusValue, err := getFieldValueFromStruct(result, c.StatusFieldName, reflect.String)
+	if err != nil {
+		return "", err
+	}
+	return statusValue.String(), nil
+}
+
+//withDefaults returns copy of configuration with non-positive initial interval
+//and multiplier lower than one replaced with DefaultWaitConfig values
+func (c *WaitConfig) withDefaults() *WaitConfig {
+	conf := *c
+	defaults := DefaultWaitConfig()
+	if conf.InitialInterval <= 0 {
+		conf.InitialInterval = defaults.InitialInterval
+	}
+	if conf.Multiplier < 1 {
+		conf.Multiplier = defaults.Multiplier
+	}
+	return &conf
+}
+
+func (c *WaitConfig) nextInterval(interval time.Duration) time.Duration {
+	if c.Multiplier > 1 {
+		interval = time.Duration(float64(interval) * c.Multiplier)
+	}
+	if c.MaxInterval > 0 && interval > c.MaxInterval {
+		interval = c.MaxInterval
+	}
+	return interval
+}
+
+//isTransientPollError reports whether poll failed with 429 Too Many Requests or 5xx response,
+//which is retried with the next poll
+func isTransientPollError(resp *resty.Response, err error) bool {
+	if resp == nil || resp.RawResponse == nil || errors.Is(err, ErrUnsupportedVersion) {
+		return false
+	}
+	return resp.StatusCode() == http.StatusTooManyRequests || resp.StatusCode() >= http.StatusInternalServerError
+}
+
+func containsString(values []string, value string) bool {
+	for i := range values {
+		if values[i] == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,156 @@
+package rest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+type TestDevice struct {
+	UUID   *string `json:"uuid"`
+	Status *string `json:"status"`
+}
+
+func TestWaitFor(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	statuses := []string{"INITIALIZING", "PROVISIONING", "PROVISIONED"}
+	mock := setupStatusResponder(resourcePath, statuses)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultWaitConfig().
+		SetSuccessStatuses("PROVISIONED").
+		SetFailureStatuses("FAILED").
+		SetInterval(time.Millisecond, 2*time.Millisecond)
+	result := TestDevice{}
+	//when
+	err := cli.WaitFor(context.Background(), resourcePath, &result, conf)
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, len(statuses), mock.GetTotalCallCount(), "Resource should be polled until success")
+	assert.Equal(t, "PROVISIONED", *result.Status, "Final resource should be returned")
+}
+
+func TestWaitForFailure(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	mock := setupStatusResponder(resourcePath, []string{"PROVISIONING", "FAILED"})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultWaitConfig().
+		SetStatusFunc(func(result interface{}) (string, error) {
+			return *result.(*TestDevice).Status, nil
+		}).
+		SetSuccessStatuses("PROVISIONED").
+		SetFailureStatuses("FAILED").
+		SetInterval(time.Millisecond, time.Millisecond)
+	//when
+	err := cli.WaitFor(context.Background(), resourcePath, &TestDevice{}, conf)
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.True(t, errors.Is(err, ErrFailureStatus), "Error should be classified as failure status")
+	assert.IsType(t, WaitError{}, err, "Error should be rest.WaitError type")
+	assert.Equal(t, "FAILED", err.(WaitError).LastStatus, "Last status should be reported")
+}
+
+func TestWaitForUnexpectedStatus(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	mock := setupStatusResponder(resourcePath, []string{"DEPROVISIONED"})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultWaitConfig().
+		SetSuccessStatuses("PROVISIONED").
+		SetPendingStatuses("PROVISIONING")
+	//when
+	err := cli.WaitFor(context.Background(), resourcePath, &TestDevice{}, conf)
+	//then
+	assert.True(t, errors.Is(err, ErrUnexpectedStatus), "Error should be classified as unexpected status")
+}
+
+func TestWaitForTimeout(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	mock := setupStatusResponder(resourcePath, []string{"PROVISIONING"})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultWaitConfig().
+		SetSuccessStatuses("PROVISIONED").
+		SetInterval(time.Millisecond, time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	//when
+	err := cli.WaitFor(ctx, resourcePath, &TestDevice{}, conf)
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error should be caused by context deadline")
+}
+
+func setupStatusResponder(path string, statuses []string) *httpmock.MockTransport {
+	mock := httpmock.NewMockTransport()
+	call := 0
+	mock.RegisterResponder(resty.MethodGet, baseURL+path,
+		func(r *http.Request) (*http.Response, error) {
+			status := statuses[call]
+			if call < len(statuses)-1 {
+				call++
+			}
+			return httpmock.NewJsonResponse(200, TestDevice{UUID: stringPtr("1"), Status: &status})
+		},
+	)
+	return mock
+}
+
+func TestWaitForNilConfig(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	mock := setupStatusResponder(resourcePath, []string{"PROVISIONED"})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	//when
+	err := cli.WaitFor(context.Background(), resourcePath, &TestDevice{}, nil)
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.Contains(t, err.Error(), "wait configuration is required", "Error should describe missing configuration")
+	assert.Equal(t, 0, mock.GetTotalCallCount(), "Resource should not be polled")
+}
+
+func TestWaitForDefaultInterval(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	mock := setupStatusResponder(resourcePath, []string{"PROVISIONING"})
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := &WaitConfig{StatusFieldName: "Status", SuccessStatuses: []string{"PROVISIONED"}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	//when
+	err := cli.WaitFor(ctx, resourcePath, &TestDevice{}, conf)
+	//then
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "Error should be caused by context deadline")
+	assert.Equal(t, 1, mock.GetTotalCallCount(), "Zero interval should be replaced with default one")
+}
+
+func TestWaitForTransientErrors(t *testing.T) {
+	//given
+	resourcePath := "/devices/1"
+	responses := []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK}
+	mock := httpmock.NewMockTransport()
+	call := 0
+	mock.RegisterResponder(resty.MethodGet, baseURL+resourcePath,
+		func(r *http.Request) (*http.Response, error) {
+			code := responses[call]
+			call++
+			return httpmock.NewJsonResponse(code, TestDevice{UUID: stringPtr("1"), Status: stringPtr("PROVISIONED")})
+		},
+	)
+	cli := NewClient(context.Background(), baseURL, &http.Client{Transport: mock})
+	conf := DefaultWaitConfig().
+		SetSuccessStatuses("PROVISIONED").
+		SetInterval(time.Millisecond, time.Millisecond)
+	//when
+	err := cli.WaitFor(context.Background(), resourcePath, &TestDevice{}, conf)
+	//then
+	assert.Nil(t, err, "Transient poll errors should be retried")
+	assert.Equal(t, 3, mock.GetTotalCallCount(), "Resource should be polled until success")
+}
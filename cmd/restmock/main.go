@@ -0,0 +1,45 @@
+//Command restmock serves directory of JSON fixtures over HTTP, so clients built
+//on Equinix REST client can be used for demos and offline debugging.
+//
+//Routes file maps request method, path and query parameters to fixture files
+//and response status codes:
+//
+//	{
+//	  "routes": [
+//	    {"path": "/objects", "query": {"page": "2"}, "fixture": "paginated_resp_p1.json"},
+//	    {"path": "/objects/{id}", "fixtures": ["provisioning.json", "provisioned.json"]},
+//	    {"method": "POST", "path": "/objects", "status": 500, "fixture": "error_resp.json"}
+//	  ]
+//	}
+//
+//Usage:
+//
+//	restmock -dir ./test-fixtures -routes ./test-fixtures/routes.json -addr localhost:8888
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory with fixture files")
+	routesFile := flag.String("routes", "", "routes file, defaults to routes.json in fixtures directory")
+	addr := flag.String("addr", "localhost:8888", "address to listen on")
+	flag.Parse()
+	logger := log.New(os.Stderr, "restmock: ", log.LstdFlags)
+	if *routesFile == "" {
+		*routesFile = filepath.Join(*dir, "routes.json")
+	}
+	routes, err := loadRoutes(*routesFile)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	logger.Printf("serving %d routes from %s on http://%s", len(routes), *dir, *addr)
+	if err := http.ListenAndServe(*addr, newHandler(*dir, routes, logger)); err != nil {
+		logger.Fatal(err)
+	}
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+//Routes describes routes file contents
+type Routes struct {
+	//Routes is a list of routes, more specific routes take precedence over less specific ones
+	Routes []Route `json:"routes"`
+}
+
+//Route maps HTTP requests to fixture files
+type Route struct {
+	//Method is HTTP method of a request, defaults to GET
+	Method string `json:"method"`
+	//Path is URL path of a request, segments in curly braces match any value
+	Path string `json:"path"`
+	//Query holds query parameters that request has to contain
+	Query map[string]string `json:"query"`
+	//Status is HTTP status code of a response, defaults to 200
+	Status int `json:"status"`
+	//Headers holds additional response headers
+	Headers map[string]string `json:"headers"`
+	//Fixture is a name of a fixture file, relative to fixtures directory, that is served as response body
+	Fixture string `json:"fixture"`
+	//Fixtures is a sequence of fixture files served on subsequent requests, last one is repeated
+	Fixtures []string `json:"fixtures"`
+}
+
+type handler struct {
+	dir    string
+	routes []Route
+	mu     sync.Mutex
+	served map[int]int
+	logger *log.Logger
+}
+
+func loadRoutes(path string) ([]Route, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read routes file: %s", err)
+	}
+	routes := Routes{}
+	if err := json.Unmarshal(data, &routes); err != nil {
+		return nil, fmt.Errorf("cannot parse routes file %q: %s", path, err)
+	}
+	for i := range routes.Routes {
+		route := &routes.Routes[i]
+		if route.Path == "" {
+			return nil, fmt.Errorf("route %d has no path", i)
+		}
+		if route.Fixture != "" && len(route.Fixtures) > 0 {
+			return nil, fmt.Errorf("route %d has both fixture and fixtures", i)
+		}
+		if route.Method == "" {
+			route.Method = http.MethodGet
+		}
+		route.Method = strings.ToUpper(route.Method)
+		if route.Status == 0 {
+			route.Status = http.StatusOK
+		}
+		if route.Fixture != "" {
+			route.Fixtures = []string{route.Fixture}
+		}
+	}
+	return routes.Routes, nil
+}
+
+func newHandler(dir string, routes []Route, logger *log.Logger) *handler {
+	return &handler{
+		dir:    dir,
+		routes: routes,
+		served: make(map[int]int),
+		logger: logger,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	index, ok := h.match(r)
+	if !ok {
+		h.logger.Printf("%s %s -> no route", r.Method, r.URL)
+		writeJSON(w, http.StatusNotFound, []byte(fmt.Sprintf(`{"errorCode":"RESTMOCK-404","errorMessage":%q}`, "no route matches "+r.Method+" "+r.URL.String())))
+		return
+	}
+	route := h.routes[index]
+	fixture := h.nextFixture(index)
+	var body []byte
+	if fixture != "" {
+		var err error
+		if body, err = ioutil.ReadFile(filepath.Join(h.dir, filepath.FromSlash(fixture))); err != nil {
+			h.logger.Printf("%s %s -> fixture error: %s", r.Method, r.URL, err)
+			writeJSON(w, http.StatusInternalServerError, []byte(fmt.Sprintf(`{"errorCode":"RESTMOCK-500","errorMessage":%q}`, err.Error())))
+			return
+		}
+	}
+	for k, v := range route.Headers {
+		w.Header().Set(k, v)
+	}
+	h.logger.Printf("%s %s -> %d %s", r.Method, r.URL, route.Status, fixture)
+	writeJSON(w, route.Status, body)
+}
+
+func (h *handler) match(r *http.Request) (int, bool) {
+	best, bestScore := -1, -1
+	query := r.URL.Query()
+	for i, route := range h.routes {
+		if route.Method != r.Method || !matchPath(route.Path, r.URL.Path) {
+			continue
+		}
+		matches := true
+		for k, v := range route.Query {
+			if query.Get(k) != v {
+				matches = false
+				break
+			}
+		}
+		if matches && len(route.Query) > bestScore {
+			best, bestScore = i, len(route.Query)
+		}
+	}
+	return best, best >= 0
+}
+
+func (h *handler) nextFixture(index int) string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	fixtures := h.routes[index].Fixtures
+	if len(fixtures) == 0 {
+		return ""
+	}
+	served := h.served[index]
+	h.served[index]++
+	if served >= len(fixtures) {
+		served = len(fixtures) - 1
+	}
+	return fixtures[served]
+}
+
+func matchPath(pattern string, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(patternSegments) != len(pathSegments) {
+		return false
+	}
+	for i := range patternSegments {
+		if strings.HasPrefix(patternSegments[i], "{") && strings.HasSuffix(patternSegments[i], "}") {
+			continue
+		}
+		if patternSegments[i] != pathSegments[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body []byte) {
+	if len(body) > 0 && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
+	w.WriteHeader(status)
+	w.Write(body)
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/equinix/rest-go"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+const fixturesDir = "../../test-fixtures"
+
+type testPaginatedResponse struct {
+	T *int         `json:"t"`
+	L []testObject `json:"l"`
+}
+
+type testOffsetPaginatedResponse struct {
+	Pagination *struct {
+		Total *int `json:"total"`
+	} `json:"pagination"`
+	Data []testObject `json:"data"`
+}
+
+type testObject struct {
+	Key *string `json:"key"`
+}
+
+func TestServeFixtures(t *testing.T) {
+	//given
+	srv := setupServer(t)
+	defer srv.Close()
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	cli.SetPageSize(1)
+	//when
+	content, err := cli.GetPaginated("/objects", &testPaginatedResponse{},
+		rest.DefaultPagingConfig().
+			SetTotalCountFieldName("T").
+			SetContentFieldName("L").
+			SetPageParamName("p").
+			SetSizeParamName("s"))
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 3, len(content), "All pages should be served")
+}
+
+func TestServeFixtureSequence(t *testing.T) {
+	//given
+	srv := setupServer(t)
+	defer srv.Close()
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	cli.SetPageSize(2)
+	//when
+	content, err := cli.GetOffsetPaginated("/offset-objects", &testOffsetPaginatedResponse{}, rest.DefaultOffsetPagingConfig())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 6, len(content), "Fixtures should be served in sequence")
+}
+
+func TestServeErrorFixture(t *testing.T) {
+	//given
+	srv := setupServer(t)
+	defer srv.Close()
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	//when
+	createErr := cli.Execute(cli.R(), resty.MethodPost, "/objects")
+	deleteErr := cli.Execute(cli.R(), resty.MethodDelete, "/objects/7kYQduPGI6")
+	unknownErr := cli.Execute(cli.R(), resty.MethodGet, "/unknown")
+	//then
+	assert.IsType(t, rest.Error{}, createErr, "Error should be rest.Error type")
+	assert.Equal(t, http.StatusInternalServerError, createErr.(rest.Error).HTTPCode, "Route status should be returned")
+	assert.Equal(t, 1, len(createErr.(rest.Error).ApplicationErrors), "Error fixture should be parsed")
+	assert.IsType(t, rest.Error{}, deleteErr, "Error should be rest.Error type")
+	assert.Equal(t, http.StatusBadRequest, deleteErr.(rest.Error).HTTPCode, "Path pattern should match")
+	assert.IsType(t, rest.Error{}, unknownErr, "Error should be rest.Error type")
+	assert.Equal(t, http.StatusNotFound, unknownErr.(rest.Error).HTTPCode, "Unknown route should not be found")
+}
+
+func TestLoadRoutesInvalid(t *testing.T) {
+	//given
+	file, err := ioutil.TempFile("", "routes")
+	if err != nil {
+		assert.Failf(t, "cannot create temp file", "error %s", err)
+	}
+	defer os.Remove(file.Name())
+	file.WriteString(`{"routes":[{"method":"GET"}]}`)
+	file.Close()
+	//when
+	_, err = loadRoutes(file.Name())
+	//then
+	assert.NotNil(t, err, "Error should be returned for route without path")
+}
+
+func setupServer(t *testing.T) *httptest.Server {
+	routes, err := loadRoutes(fixturesDir + "/routes.json")
+	if err != nil {
+		assert.Failf(t, "cannot load routes", "error %s", err)
+	}
+	return httptest.NewServer(newHandler(fixturesDir, routes, log.New(ioutil.Discard, "", 0)))
+}
@@ -6,4 +6,5 @@ require (
 	github.com/go-resty/resty/v2 v2.3.0
 	github.com/jarcoal/httpmock v1.0.6
 	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,108 @@
+package redact
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//Placeholder replaces redacted values
+const Placeholder = "REDACTED"
+
+//DefaultHeaders is a list of headers that carry secrets
+var DefaultHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Auth-Token"}
+
+//DefaultFields is a list of JSON body fields that carry secrets
+var DefaultFields = []string{"password", "client_secret", "clientSecret", "access_token", "refresh_token", "accessToken", "refreshToken"}
+
+//DefaultQueryParams is a list of URL query parameters that carry secrets
+var DefaultQueryParams = []string{"access_token", "refresh_token", "token", "client_secret", "clientSecret", "password", "api_key", "apiKey"}
+
+//Header returns copy of given header with values of given headers replaced by a placeholder
+func Header(header http.Header, names []string) http.Header {
+	redacted := header.Clone()
+	if redacted == nil {
+		return redacted
+	}
+	for _, name := range names {
+		if values, ok := redacted[http.CanonicalHeaderKey(name)]; ok {
+			for i := range values {
+				values[i] = Placeholder
+			}
+		}
+	}
+	return redacted
+}
+
+//URL returns copy of given URL with values of given query parameters replaced by a placeholder
+func URL(u *url.URL, names []string) *url.URL {
+	redacted := *u
+	if u.RawQuery == "" || len(names) == 0 {
+		return &redacted
+	}
+	query := u.Query()
+	changed := false
+	for name, values := range query {
+		if !containsFold(names, name) {
+			continue
+		}
+		for i := range values {
+			values[i] = Placeholder
+		}
+		changed = true
+	}
+	if changed {
+		redacted.RawQuery = query.Encode()
+	}
+	return &redacted
+}
+
+//Body returns given body with values of given JSON fields, at any depth, replaced by
+//a placeholder. Body that is not a valid JSON is returned unchanged
+func Body(body []byte, fields []string) []byte {
+	if len(body) == 0 || len(fields) == 0 {
+		return body
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	if !redactValue(value, fields) {
+		return body
+	}
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+func redactValue(value interface{}, fields []string) bool {
+	changed := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			if containsFold(fields, k) {
+				v[k] = Placeholder
+				changed = true
+				continue
+			}
+			changed = redactValue(v[k], fields) || changed
+		}
+	case []interface{}:
+		for i := range v {
+			changed = redactValue(v[i], fields) || changed
+		}
+	}
+	return changed
+}
+
+func containsFold(values []string, value string) bool {
+	for i := range values {
+		if strings.EqualFold(values[i], value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package rest
+
+import "container/list"
+
+//lruCache is a map that holds up to given number of entries and evicts least
+//recently used ones. Capacity that is not positive means no limit.
+//lruCache is not safe for concurrent use
+type lruCache struct {
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type lruItem struct {
+	key   string
+	value interface{}
+}
+
+func newLRUCache(capacity int) *lruCache {
+	return &lruCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (c *lruCache) get(key string) (interface{}, bool) {
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruItem).value, true
+}
+
+func (c *lruCache) set(key string, value interface{}) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*lruItem).value = value
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key, value})
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		last := c.order.Back()
+		c.order.Remove(last)
+		delete(c.entries, last.Value.(*lruItem).key)
+	}
+}
+
+func (c *lruCache) delete(key string) {
+	if elem, ok := c.entries[key]; ok {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+	}
+}
+
+func (c *lruCache) len() int {
+	return c.order.Len()
+}
@@ -0,0 +1,191 @@
+package resttest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"regexp"
+	"sync"
+	"syscall"
+	"time"
+)
+
+//Fault describes fault injected into HTTP round trip. Fault may use given
+//next round tripper to perform actual request
+type Fault func(req *http.Request, next http.RoundTripper) (*http.Response, error)
+
+//FaultRule describes which requests are affected by a fault
+type FaultRule struct {
+	//Method is HTTP method of affected requests, empty matches any method
+	Method string
+	//Path is a regular expression matched against path of affected requests, nil matches any path
+	Path *regexp.Regexp
+	//Probability is a probability, between 0 and 1, of injecting a fault into matching request
+	Probability float64
+	//Fault is injected fault
+	Fault Fault
+}
+
+//FaultTransport is http.RoundTripper that injects faults into requests before passing them
+//to underlying transport. Rules are evaluated in order and first triggered rule is applied.
+//FaultTransport can be used with http.Client given to rest.NewClient
+type FaultTransport struct {
+	next     http.RoundTripper
+	mu       sync.Mutex
+	rules    []FaultRule
+	random   *rand.Rand
+	injected int
+}
+
+//NewFaultTransport creates new fault injecting transport on top of a given transport.
+//When given transport is nil, http.DefaultTransport is used
+func NewFaultTransport(next http.RoundTripper) *FaultTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &FaultTransport{
+		next:   next,
+		random: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+//AddRule adds fault injection rule
+func (t *FaultTransport) AddRule(rule FaultRule) *FaultTransport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rules = append(t.rules, rule)
+	return t
+}
+
+//Inject adds rule that always injects given fault into requests with a given method and path matching given regular expression.
+//Empty method or path matches any request
+func (t *FaultTransport) Inject(method string, path string, fault Fault) *FaultTransport {
+	rule := FaultRule{Method: method, Probability: 1, Fault: fault}
+	if path != "" {
+		rule.Path = regexp.MustCompile(path)
+	}
+	return t.AddRule(rule)
+}
+
+//SetSeed sets seed of random number generator used to evaluate rule probabilities
+func (t *FaultTransport) SetSeed(seed int64) *FaultTransport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.random = rand.New(rand.NewSource(seed))
+	return t
+}
+
+//Injected returns number of faults injected so far
+func (t *FaultTransport) Injected() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.injected
+}
+
+//RoundTrip executes single HTTP transaction, possibly injecting a fault
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if fault := t.trigger(req); fault != nil {
+		return fault(req, t.next)
+	}
+	return t.next.RoundTrip(req)
+}
+
+//Latency returns fault that delays request by a given duration
+func Latency(d time.Duration) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-req.Context().Done():
+			closeRequestBody(req)
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		return next.RoundTrip(req)
+	}
+}
+
+//Status returns fault that responds with a given status code and body without passing request further
+func Status(statusCode int, body string) Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(req)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			StatusCode:    statusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+}
+
+//ConnectionReset returns fault that fails request with connection reset error without passing request further
+func ConnectionReset() Fault {
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		closeRequestBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	}
+}
+
+//TruncatedBody returns fault that passes request further and truncates response body to a given fraction of its length.
+//Fraction is clamped to range between 0 and 1
+func TruncatedBody(fraction float64) Fault {
+	if fraction < 0 {
+		fraction = 0
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	return func(req *http.Request, next http.RoundTripper) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = body[:int(float64(len(body))*fraction)]
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (t *FaultTransport) trigger(req *http.Request) Fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, rule := range t.rules {
+		if rule.Method != "" && rule.Method != req.Method {
+			continue
+		}
+		if rule.Path != nil && !rule.Path.MatchString(req.URL.Path) {
+			continue
+		}
+		if t.random.Float64() >= rule.Probability {
+			continue
+		}
+		t.injected++
+		return rule.Fault
+	}
+	return nil
+}
+
+func closeRequestBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
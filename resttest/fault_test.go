@@ -0,0 +1,114 @@
+package resttest
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/equinix/rest-go"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultStatus(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	transport := NewFaultTransport(nil).Inject(resty.MethodGet, "^/ne/v1/devices$", Status(http.StatusBadGateway, ""))
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: transport})
+	//when
+	listErr := cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices")
+	createErr := cli.Execute(cli.R().SetBody(map[string]string{}), resty.MethodPost, "/ne/v1/devices")
+	//then
+	assert.IsType(t, rest.Error{}, listErr, "Error should be rest.Error type")
+	assert.Equal(t, http.StatusBadGateway, listErr.(rest.Error).HTTPCode, "Injected status should be returned")
+	assert.Nil(t, createErr, "Not matching request should not be affected")
+	assert.Equal(t, 1, transport.Injected(), "One fault should be injected")
+}
+
+func TestFaultConnectionReset(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	transport := NewFaultTransport(nil).Inject("", "", ConnectionReset())
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: transport})
+	//when
+	err := cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices")
+	//then
+	assert.NotNil(t, err, "Error should be returned")
+	assert.Regexp(t, regexp.MustCompile("connection reset"), err.Error(), "Error should describe connection reset")
+}
+
+func TestFaultTruncatedBody(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	devices := srv.Collection("/ne/v1/devices")
+	devices.Put("/ne/v1/devices", map[string]string{"name": "router"})
+	transport := NewFaultTransport(nil).Inject(resty.MethodGet, "", TruncatedBody(0.5))
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: transport})
+	//when
+	err := cli.Execute(cli.R().SetResult(&testDevicesPage{}), resty.MethodGet, "/ne/v1/devices")
+	//then
+	assert.NotNil(t, err, "Error should be returned for truncated JSON")
+}
+
+func TestFaultTruncatedBodyFractionClamped(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	devices := srv.Collection("/ne/v1/devices")
+	devices.Put("/ne/v1/devices", map[string]string{"name": "router"})
+	negative := NewFaultTransport(nil).Inject(resty.MethodGet, "", TruncatedBody(-0.5))
+	excessive := NewFaultTransport(nil).Inject(resty.MethodGet, "", TruncatedBody(1.5))
+	negativeCli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: negative})
+	excessiveCli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: excessive})
+	//when
+	negativeResp, negativeErr := negativeCli.Do(resty.MethodGet, "/ne/v1/devices", negativeCli.R())
+	excessiveResp, excessiveErr := excessiveCli.Do(resty.MethodGet, "/ne/v1/devices", excessiveCli.R().SetResult(&testDevicesPage{}))
+	//then
+	assert.Nil(t, negativeErr, "Error should not be returned")
+	assert.Empty(t, negativeResp.Body(), "Negative fraction should truncate whole body")
+	assert.Nil(t, excessiveErr, "Fraction above one should keep whole body")
+	assert.NotContains(t, string(excessiveResp.Body()), "\x00", "Body should not be padded")
+}
+
+func TestFaultLatency(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	latency := 20 * time.Millisecond
+	transport := NewFaultTransport(nil).Inject("", "", Latency(latency))
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: transport})
+	//when
+	start := time.Now()
+	err := cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices")
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.True(t, time.Since(start) >= latency, "Request should be delayed")
+}
+
+func TestFaultProbability(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	transport := NewFaultTransport(nil).SetSeed(1).
+		AddRule(FaultRule{Probability: 0.5, Fault: Status(http.StatusServiceUnavailable, "")})
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: transport})
+	//when
+	failures := 0
+	for i := 0; i < 100; i++ {
+		if err := cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices"); err != nil {
+			failures++
+		}
+	}
+	//then
+	assert.Equal(t, transport.Injected(), failures, "Every injected fault should fail request")
+	assert.True(t, failures > 20 && failures < 80, "Faults should be injected with given probability")
+}
@@ -0,0 +1,286 @@
+package resttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+
+	"github.com/equinix/rest-go/internal/redact"
+)
+
+//RecorderMode determines whether Recorder records or replays traffic
+type RecorderMode int
+
+const (
+	//ModeReplay makes Recorder serve responses from a cassette without network access
+	ModeReplay RecorderMode = iota
+	//ModeRecord makes Recorder pass requests to underlying transport and record them
+	ModeRecord
+)
+
+//Cassette holds recorded HTTP interactions
+type Cassette struct {
+	//Interactions is a list of recorded interactions in order of recording
+	Interactions []Interaction `json:"interactions"`
+}
+
+//Interaction describes recorded HTTP request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+//RecordedRequest describes recorded HTTP request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+//RecordedResponse describes recorded HTTP response
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+//Matcher determines which request attributes are compared when replayed request
+//is matched with recorded interactions
+type Matcher struct {
+	//Method enables comparison of HTTP methods
+	Method bool
+	//Path enables comparison of URL paths
+	Path bool
+	//Query enables comparison of query parameters, regardless of their order
+	Query bool
+	//Body enables comparison of request bodies, JSON bodies are compared semantically
+	Body bool
+}
+
+//Recorder is http.RoundTripper that records HTTP interactions into a cassette file
+//or replays them from it. Secrets in headers, query parameters and JSON bodies are
+//redacted before recording. Recorder can be used with http.Client given to rest.NewClient
+type Recorder struct {
+	path          string
+	mode          RecorderMode
+	next          http.RoundTripper
+	matcher       Matcher
+	redactHeaders []string
+	redactFields  []string
+	redactQuery   []string
+	mu            sync.Mutex
+	cassette      Cassette
+	used          []bool
+}
+
+//DefaultMatcher returns Matcher that compares method, path and query parameters
+func DefaultMatcher() Matcher {
+	return Matcher{Method: true, Path: true, Query: true}
+}
+
+//NewRecorder creates new recorder that uses cassette file with a given path. In replay mode
+//cassette is loaded from a file. In record mode requests are passed to a given transport,
+//or http.DefaultTransport when it is nil, and cassette is saved on Stop
+func NewRecorder(path string, mode RecorderMode, next http.RoundTripper) (*Recorder, error) {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r := &Recorder{
+		path:          path,
+		mode:          mode,
+		next:          next,
+		matcher:       DefaultMatcher(),
+		redactHeaders: redact.DefaultHeaders,
+		redactFields:  redact.DefaultFields,
+		redactQuery:   redact.DefaultQueryParams,
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read cassette: %s", err)
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cannot parse cassette %q: %s", path, err)
+	}
+	r.used = make([]bool, len(r.cassette.Interactions))
+	return r, nil
+}
+
+//SetMatcher sets matcher used to find recorded interactions in replay mode
+func (r *Recorder) SetMatcher(m Matcher) *Recorder {
+	r.matcher = m
+	return r
+}
+
+//SetRedactedHeaders sets names of headers which values are redacted
+func (r *Recorder) SetRedactedHeaders(names ...string) *Recorder {
+	r.redactHeaders = names
+	return r
+}
+
+//SetRedactedFields sets names of JSON body fields which values are redacted
+func (r *Recorder) SetRedactedFields(names ...string) *Recorder {
+	r.redactFields = names
+	return r
+}
+
+//SetRedactedQueryParams sets names of URL query parameters which values are redacted
+func (r *Recorder) SetRedactedQueryParams(names ...string) *Recorder {
+	r.redactQuery = names
+	return r
+}
+
+//Unused returns recorded interactions that were not replayed
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var unused []Interaction
+	for i := range r.used {
+		if !r.used[i] {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+//Stop saves recorded interactions into a cassette file. It has no effect in replay mode
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, data, 0600)
+}
+
+//RoundTrip records or replays single HTTP transaction
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	reqBody = redact.Body(reqBody, r.redactFields)
+	if r.mode == ModeRecord {
+		return r.record(req, reqBody)
+	}
+	return r.replay(req, reqBody)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (r *Recorder) record(req *http.Request, reqBody []byte) (*http.Response, error) {
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return resp, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    redact.URL(req.URL, r.redactQuery).String(),
+			Header: redact.Header(req.Header, r.redactHeaders),
+			Body:   string(reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     redact.Header(resp.Header, r.redactHeaders),
+			Body:       string(redact.Body(respBody, r.redactFields)),
+		},
+	})
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, reqBody []byte) (*http.Response, error) {
+	matchReq := *req
+	matchReq.URL = redact.URL(req.URL, r.redactQuery)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !r.matcher.matches(&matchReq, reqBody, interaction.Request) {
+			continue
+		}
+		r.used[i] = true
+		recorded := interaction.Response
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        recorded.Header.Clone(),
+			Body:          ioutil.NopCloser(bytes.NewBufferString(recorded.Body)),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction matches %s %s", req.Method, req.URL)
+}
+
+func (m Matcher) matches(req *http.Request, reqBody []byte, recorded RecordedRequest) bool {
+	recordedURL, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	if m.Method && req.Method != recorded.Method {
+		return false
+	}
+	if m.Path && req.URL.EscapedPath() != recordedURL.EscapedPath() {
+		return false
+	}
+	if m.Query && !reflect.DeepEqual(normalizeQuery(req.URL.Query()), normalizeQuery(recordedURL.Query())) {
+		return false
+	}
+	if m.Body && !jsonEqual(reqBody, []byte(recorded.Body)) {
+		return false
+	}
+	return true
+}
+
+func normalizeQuery(values url.Values) url.Values {
+	if len(values) == 0 {
+		return nil
+	}
+	return values
+}
+
+func jsonEqual(a []byte, b []byte) bool {
+	var av, bv interface{}
+	if json.Unmarshal(a, &av) != nil || json.Unmarshal(b, &bv) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(av, bv)
+}
+
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
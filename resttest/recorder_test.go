@@ -0,0 +1,68 @@
+package resttest
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/equinix/rest-go"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	//given
+	dir, err := ioutil.TempDir("", "rest-cassette")
+	if err != nil {
+		assert.Failf(t, "cannot create temp dir", "error %s", err)
+	}
+	defer os.RemoveAll(dir)
+	cassettePath := filepath.Join(dir, "devices.json")
+	srv := NewServer()
+	srv.Collection("/ne/v1/devices")
+	recorder, err := NewRecorder(cassettePath, ModeRecord, nil)
+	assert.Nil(t, err, "Error should not be returned")
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: recorder})
+	cli.SetAuthToken("secret-token")
+	created := testDevice{}
+	createErr := cli.Execute(cli.R().SetBody(map[string]string{"name": "router", "password": "secret-password"}).SetResult(&created), resty.MethodPost, "/ne/v1/devices")
+	readErr := cli.Execute(cli.R().SetQueryParam("access_token", "secret-query-token"), resty.MethodGet, "/ne/v1/devices/"+*created.UUID)
+	assert.Nil(t, createErr, "Error should not be returned")
+	assert.Nil(t, readErr, "Error should not be returned")
+	assert.Nil(t, recorder.Stop(), "Cassette should be saved")
+	srv.Close()
+	//when
+	replayer, err := NewRecorder(cassettePath, ModeReplay, nil)
+	assert.Nil(t, err, "Error should not be returned")
+	replayCli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: replayer})
+	replayed := testDevice{}
+	replayErr := replayCli.Execute(replayCli.R().SetQueryParam("access_token", "other-token").SetResult(&replayed), resty.MethodGet, "/ne/v1/devices/"+*created.UUID)
+	unmatchedErr := replayCli.Execute(replayCli.R(), resty.MethodGet, "/ne/v1/devices/unknown")
+	//then
+	cassette, _ := ioutil.ReadFile(cassettePath)
+	assert.False(t, strings.Contains(string(cassette), "secret-token"), "Authorization header should be redacted")
+	assert.False(t, strings.Contains(string(cassette), "secret-password"), "Password should be redacted")
+	assert.False(t, strings.Contains(string(cassette), "secret-query-token"), "Query token should be redacted")
+	assert.Nil(t, replayErr, "Error should not be returned")
+	assert.Equal(t, "router", *replayed.Name, "Recorded response should be replayed")
+	assert.NotNil(t, unmatchedErr, "Unmatched request should fail")
+	assert.Equal(t, 1, len(replayer.Unused()), "Create interaction should not be replayed")
+}
+
+func TestMatcherBody(t *testing.T) {
+	//given
+	recorded := RecordedRequest{Method: "POST", URL: "http://localhost/objects?b=2&a=1", Body: `{"name":"x","size":1}`}
+	req, _ := http.NewRequest("POST", "http://localhost/objects?a=1&b=2", nil)
+	matcher := DefaultMatcher()
+	matcher.Body = true
+	//when
+	matchesSameBody := matcher.matches(req, []byte(`{"size":1,"name":"x"}`), recorded)
+	matchesOtherBody := matcher.matches(req, []byte(`{"size":2,"name":"x"}`), recorded)
+	//then
+	assert.True(t, matchesSameBody, "Equivalent JSON body and query should match")
+	assert.False(t, matchesOtherBody, "Different JSON body should not match")
+}
@@ -0,0 +1,489 @@
+//Package resttest implements in-process fake Equinix REST API server for tests
+package resttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/equinix/rest-go"
+	"github.com/equinix/rest-go/internal/api"
+)
+
+//Server is in-process HTTP server that stores resources of registered
+//collections in memory and serves them with CRUD operations
+type Server struct {
+	*httptest.Server
+	mu          sync.Mutex
+	collections []*Collection
+	errors      []*errorRule
+	lastID      int
+}
+
+//Collection describes resource collection served under a path pattern.
+//Pattern segments in curly braces, like /ne/v1/devices/{uuid}/acls, match any value
+type Collection struct {
+	//Pattern is a path pattern of a collection
+	Pattern string
+	//IDField is a name of resource JSON field that holds resource identifier
+	IDField string
+	//Pagination describes how collection listing is paginated
+	Pagination Pagination
+	server     *Server
+	items      map[string]*items
+}
+
+//Pagination describes how collection listing responses are paginated
+type Pagination interface {
+	paginate(r *http.Request, resources []interface{}) (interface{}, error)
+}
+
+//PageNumberPagination emulates pagination with page number and page size query parameters
+type PageNumberPagination struct {
+	//SizeParamName is a name of page size query parameter
+	SizeParamName string
+	//PageParamName is a name of page number query parameter
+	PageParamName string
+	//FirstPageNumber is a number of a first page (typically 0 or 1)
+	FirstPageNumber int
+	//DefaultSize is a page size used when size query parameter is absent
+	DefaultSize int
+	//TotalCountFieldName is a name of response field with total number of elements
+	TotalCountFieldName string
+	//PageNumberFieldName is a name of response field with page number
+	PageNumberFieldName string
+	//PageSizeFieldName is a name of response field with page size
+	PageSizeFieldName string
+	//ContentFieldName is a name of response field with page elements
+	ContentFieldName string
+}
+
+//OffsetPagination emulates pagination with offset and limit query parameters
+//and a separate pagination object in a response
+type OffsetPagination struct {
+	//OffsetParamName is a name of offset query parameter
+	OffsetParamName string
+	//LimitParamName is a name of limit query parameter
+	LimitParamName string
+	//DefaultLimit is a limit used when limit query parameter is absent
+	DefaultLimit int
+	//PaginationFieldName is a name of response field with pagination object
+	PaginationFieldName string
+	//OffsetFieldName is a name of pagination object field with offset
+	OffsetFieldName string
+	//LimitFieldName is a name of pagination object field with limit
+	LimitFieldName string
+	//TotalFieldName is a name of pagination object field with total number of elements
+	TotalFieldName string
+	//DataFieldName is a name of response field with page elements
+	DataFieldName string
+}
+
+//NewServer creates and starts new fake server. Server should be closed with Close when no longer needed
+func NewServer() *Server {
+	s := &Server{}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+//DefaultPageNumberPagination returns PageNumberPagination with default values
+func DefaultPageNumberPagination() *PageNumberPagination {
+	return &PageNumberPagination{
+		SizeParamName:       "size",
+		PageParamName:       "page",
+		FirstPageNumber:     1,
+		DefaultSize:         20,
+		TotalCountFieldName: "totalCount",
+		PageNumberFieldName: "pageNumber",
+		PageSizeFieldName:   "pageSize",
+		ContentFieldName:    "content",
+	}
+}
+
+//DefaultOffsetPagination returns OffsetPagination with default values
+func DefaultOffsetPagination() *OffsetPagination {
+	return &OffsetPagination{
+		OffsetParamName:     "offset",
+		LimitParamName:      "limit",
+		DefaultLimit:        20,
+		PaginationFieldName: "pagination",
+		OffsetFieldName:     "offset",
+		LimitFieldName:      "limit",
+		TotalFieldName:      "total",
+		DataFieldName:       "data",
+	}
+}
+
+//Collection registers new resource collection under a given path pattern. By default resources
+//are identified by uuid field and listings are paginated with PageNumberPagination
+func (s *Server) Collection(pattern string) *Collection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := &Collection{
+		Pattern:    "/" + strings.Trim(pattern, "/"),
+		IDField:    "uuid",
+		Pagination: DefaultPageNumberPagination(),
+		server:     s,
+		items:      make(map[string]*items),
+	}
+	s.collections = append(s.collections, c)
+	return c
+}
+
+//Fail makes server respond with a given status code and Equinix formatted application errors to
+//requests with a given method and path. Error is returned given number of times, or always when times is not positive
+func (s *Server) Fail(method string, path string, statusCode int, times int, appErrors ...rest.ApplicationError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, &errorRule{
+		method:     method,
+		path:       "/" + strings.Trim(path, "/"),
+		statusCode: statusCode,
+		remaining:  times,
+		appErrors:  appErrors,
+	})
+}
+
+//SetIDField sets name of resource JSON field that holds resource identifier
+func (c *Collection) SetIDField(v string) *Collection {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	c.IDField = v
+	return c
+}
+
+//SetPagination sets pagination of collection listing
+func (c *Collection) SetPagination(v Pagination) *Collection {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	c.Pagination = v
+	return c
+}
+
+//Put stores given resource in a collection under a given concrete collection path.
+//Resource is converted to JSON object, its identifier is taken from IDField or generated
+func (c *Collection) Put(path string, resource interface{}) (string, error) {
+	object, err := toObject(resource)
+	if err != nil {
+		return "", err
+	}
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	return c.store("/"+strings.Trim(path, "/"), object), nil
+}
+
+//Get returns resource with a given identifier stored under a given concrete collection path
+func (c *Collection) Get(path string, id string) (map[string]interface{}, bool) {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	list, ok := c.items["/"+strings.Trim(path, "/")]
+	if !ok {
+		return nil, false
+	}
+	object, ok := list.objects[id]
+	return object, ok
+}
+
+//Len returns number of resources stored under a given concrete collection path
+func (c *Collection) Len(path string) int {
+	c.server.mu.Lock()
+	defer c.server.mu.Unlock()
+	list, ok := c.items["/"+strings.Trim(path, "/")]
+	if !ok {
+		return 0
+	}
+	return len(list.ids)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+type items struct {
+	ids     []string
+	objects map[string]map[string]interface{}
+}
+
+type errorRule struct {
+	method     string
+	path       string
+	statusCode int
+	remaining  int
+	appErrors  []rest.ApplicationError
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := "/" + strings.Trim(r.URL.EscapedPath(), "/")
+	if rule := s.matchError(r.Method, path); rule != nil {
+		writeError(w, rule.statusCode, rule.appErrors...)
+		return
+	}
+	for _, c := range s.collections {
+		if collectionPath, id, ok := c.match(path); ok {
+			c.serve(w, r, collectionPath, id)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, rest.ApplicationError{
+		Code:    "NOT_FOUND",
+		Message: fmt.Sprintf("no collection matches path %q", path),
+	})
+}
+
+func (s *Server) matchError(method string, path string) *errorRule {
+	for i, rule := range s.errors {
+		if rule.method != method || rule.path != path {
+			continue
+		}
+		if rule.remaining > 0 {
+			rule.remaining--
+			if rule.remaining == 0 {
+				s.errors = append(s.errors[:i], s.errors[i+1:]...)
+			}
+		}
+		return rule
+	}
+	return nil
+}
+
+func (s *Server) nextID() string {
+	s.lastID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.lastID)
+}
+
+func (c *Collection) match(path string) (string, string, bool) {
+	patternSegments := strings.Split(strings.Trim(c.Pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(pathSegments) != len(patternSegments) && len(pathSegments) != len(patternSegments)+1 {
+		return "", "", false
+	}
+	for i := range patternSegments {
+		if strings.HasPrefix(patternSegments[i], "{") && strings.HasSuffix(patternSegments[i], "}") {
+			continue
+		}
+		if patternSegments[i] != pathSegments[i] {
+			return "", "", false
+		}
+	}
+	collectionPath := "/" + strings.Join(pathSegments[:len(patternSegments)], "/")
+	if len(pathSegments) == len(patternSegments) {
+		return collectionPath, "", true
+	}
+	return collectionPath, pathSegments[len(patternSegments)], true
+}
+
+func (c *Collection) serve(w http.ResponseWriter, r *http.Request, collectionPath string, id string) {
+	list := c.items[collectionPath]
+	if id == "" {
+		switch r.Method {
+		case http.MethodGet:
+			c.list(w, r, list)
+		case http.MethodPost:
+			c.create(w, r, collectionPath)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, rest.ApplicationError{Code: "METHOD_NOT_ALLOWED", Message: r.Method})
+		}
+		return
+	}
+	var object map[string]interface{}
+	if list != nil {
+		object = list.objects[id]
+	}
+	if object == nil {
+		writeError(w, http.StatusNotFound, rest.ApplicationError{
+			Code:     "NOT_FOUND",
+			Property: c.IDField,
+			Message:  fmt.Sprintf("resource %q not found", id),
+		})
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, object)
+	case http.MethodPut, http.MethodPatch:
+		update, ok := readObject(w, r)
+		if !ok {
+			return
+		}
+		if r.Method == http.MethodPut {
+			object = make(map[string]interface{})
+		}
+		for k, v := range update {
+			object[k] = v
+		}
+		object[c.IDField] = id
+		list.objects[id] = object
+		writeJSON(w, http.StatusOK, object)
+	case http.MethodDelete:
+		delete(list.objects, id)
+		for i := range list.ids {
+			if list.ids[i] == id {
+				list.ids = append(list.ids[:i], list.ids[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, rest.ApplicationError{Code: "METHOD_NOT_ALLOWED", Message: r.Method})
+	}
+}
+
+func (c *Collection) list(w http.ResponseWriter, r *http.Request, list *items) {
+	resources := make([]interface{}, 0)
+	if list != nil {
+		for _, id := range list.ids {
+			resources = append(resources, list.objects[id])
+		}
+	}
+	page, err := c.Pagination.paginate(r, resources)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, rest.ApplicationError{Code: "INVALID_QUERY", Message: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (c *Collection) create(w http.ResponseWriter, r *http.Request, collectionPath string) {
+	object, ok := readObject(w, r)
+	if !ok {
+		return
+	}
+	id := c.store(collectionPath, object)
+	w.Header().Set("Location", collectionPath+"/"+id)
+	writeJSON(w, http.StatusCreated, object)
+}
+
+func (c *Collection) store(collectionPath string, object map[string]interface{}) string {
+	id, _ := object[c.IDField].(string)
+	if id == "" {
+		id = c.server.nextID()
+		object[c.IDField] = id
+	}
+	list, ok := c.items[collectionPath]
+	if !ok {
+		list = &items{objects: make(map[string]map[string]interface{})}
+		c.items[collectionPath] = list
+	}
+	if _, exists := list.objects[id]; !exists {
+		list.ids = append(list.ids, id)
+	}
+	list.objects[id] = object
+	return id
+}
+
+func (p *PageNumberPagination) paginate(r *http.Request, resources []interface{}) (interface{}, error) {
+	size, err := intQueryParam(r, p.SizeParamName, p.DefaultSize)
+	if err != nil {
+		return nil, err
+	}
+	page, err := intQueryParam(r, p.PageParamName, p.FirstPageNumber)
+	if err != nil {
+		return nil, err
+	}
+	if size <= 0 || page < p.FirstPageNumber {
+		return nil, fmt.Errorf("invalid page %d or size %d", page, size)
+	}
+	return map[string]interface{}{
+		p.TotalCountFieldName: len(resources),
+		p.PageNumberFieldName: page,
+		p.PageSizeFieldName:   size,
+		p.ContentFieldName:    window(resources, (page-p.FirstPageNumber)*size, size),
+	}, nil
+}
+
+func (p *OffsetPagination) paginate(r *http.Request, resources []interface{}) (interface{}, error) {
+	limit, err := intQueryParam(r, p.LimitParamName, p.DefaultLimit)
+	if err != nil {
+		return nil, err
+	}
+	offset, err := intQueryParam(r, p.OffsetParamName, 0)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 || offset < 0 {
+		return nil, fmt.Errorf("invalid offset %d or limit %d", offset, limit)
+	}
+	return map[string]interface{}{
+		p.PaginationFieldName: map[string]interface{}{
+			p.OffsetFieldName: offset,
+			p.LimitFieldName:  limit,
+			p.TotalFieldName:  len(resources),
+		},
+		p.DataFieldName: window(resources, offset, limit),
+	}, nil
+}
+
+func window(resources []interface{}, start int, size int) []interface{} {
+	if start >= len(resources) {
+		return []interface{}{}
+	}
+	end := start + size
+	if end > len(resources) {
+		end = len(resources)
+	}
+	return resources[start:end]
+}
+
+func intQueryParam(r *http.Request, name string, defaultValue int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("query parameter %q is not a number", name)
+	}
+	return parsed, nil
+}
+
+func readObject(w http.ResponseWriter, r *http.Request) (map[string]interface{}, bool) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, rest.ApplicationError{Code: "INVALID_BODY", Message: err.Error()})
+		return nil, false
+	}
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(body, &object); err != nil {
+		writeError(w, http.StatusBadRequest, rest.ApplicationError{Code: "INVALID_BODY", Message: err.Error()})
+		return nil, false
+	}
+	return object, true
+}
+
+func toObject(resource interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	object := make(map[string]interface{})
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, fmt.Errorf("resource is not a JSON object: %s", err)
+	}
+	return object, nil
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, appErrors ...rest.ApplicationError) {
+	apiErrors := make(api.ErrorResponses, len(appErrors))
+	for i := range appErrors {
+		apiErrors[i] = api.ErrorResponse{
+			ErrorCode:    appErrors[i].Code,
+			ErrorMessage: appErrors[i].Message,
+			MoreInfo:     appErrors[i].AdditionalInfo,
+			Property:     appErrors[i].Property,
+		}
+	}
+	writeJSON(w, statusCode, apiErrors)
+}
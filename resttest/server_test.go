@@ -0,0 +1,127 @@
+package resttest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/equinix/rest-go"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDevice struct {
+	UUID *string `json:"uuid,omitempty"`
+	Name *string `json:"name,omitempty"`
+}
+
+type testDevicesPage struct {
+	TotalCount *int         `json:"totalCount"`
+	Content    []testDevice `json:"content"`
+}
+
+type testConnectionsPage struct {
+	Pagination *struct {
+		Total *int `json:"total"`
+	} `json:"pagination"`
+	Data []testDevice `json:"data"`
+}
+
+func TestCreateThenRead(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	name := "router"
+	created := testDevice{}
+	//when
+	resp, err := cli.Do(resty.MethodPost, "/ne/v1/devices", cli.R().SetBody(testDevice{Name: &name}).SetResult(&created))
+	assert.Nil(t, err, "Error should not be returned")
+	read := testDevice{}
+	readErr := cli.Execute(cli.R().SetResult(&read), resty.MethodGet, "/ne/v1/devices/"+*created.UUID)
+	//then
+	assert.Nil(t, readErr, "Error should not be returned")
+	assert.Equal(t, http.StatusCreated, resp.StatusCode(), "Resource should be created")
+	assert.Equal(t, "/ne/v1/devices/"+*created.UUID, resp.Header().Get("Location"), "Location should point at resource")
+	assert.Equal(t, name, *read.Name, "Created resource should be read")
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	acls := srv.Collection("/ne/v1/devices/{uuid}/acls")
+	id, _ := acls.Put("/ne/v1/devices/1/acls", map[string]string{"name": "acl"})
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	//when
+	patchErr := cli.Execute(cli.R().SetBody(map[string]string{"description": "updated"}), resty.MethodPatch, "/ne/v1/devices/1/acls/"+id)
+	patched, _ := acls.Get("/ne/v1/devices/1/acls", id)
+	deleteErr := cli.Execute(cli.R(), resty.MethodDelete, "/ne/v1/devices/1/acls/"+id)
+	readErr := cli.Execute(cli.R(), resty.MethodGet, "/ne/v1/devices/1/acls/"+id)
+	//then
+	assert.Nil(t, patchErr, "Error should not be returned")
+	assert.Equal(t, "acl", patched["name"], "Patch should keep existing fields")
+	assert.Equal(t, "updated", patched["description"], "Patch should set new fields")
+	assert.Nil(t, deleteErr, "Error should not be returned")
+	assert.Equal(t, 0, acls.Len("/ne/v1/devices/1/acls"), "Resource should be deleted")
+	assert.IsType(t, rest.Error{}, readErr, "Error should be rest.Error type")
+	assert.Equal(t, http.StatusNotFound, readErr.(rest.Error).HTTPCode, "Deleted resource should not be found")
+}
+
+func TestPageNumberPagination(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	devices := srv.Collection("/ne/v1/devices")
+	for i := 0; i < 5; i++ {
+		devices.Put("/ne/v1/devices", map[string]string{})
+	}
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	cli.SetPageSize(2)
+	//when
+	content, err := cli.GetPaginated("/ne/v1/devices", &testDevicesPage{}, rest.DefaultPagingConfig())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 5, len(content), "All resources should be listed")
+}
+
+func TestOffsetPagination(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	connections := srv.Collection("/fabric/v4/connections").SetPagination(DefaultOffsetPagination())
+	for i := 0; i < 5; i++ {
+		connections.Put("/fabric/v4/connections", map[string]string{})
+	}
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	cli.SetPageSize(2)
+	//when
+	content, err := cli.GetOffsetPaginated("/fabric/v4/connections", &testConnectionsPage{}, rest.DefaultOffsetPagingConfig())
+	//then
+	assert.Nil(t, err, "Error should not be returned")
+	assert.Equal(t, 5, len(content), "All resources should be listed")
+}
+
+func TestFail(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	srv.Fail(resty.MethodPost, "/ne/v1/devices", http.StatusBadRequest, 1, rest.ApplicationError{
+		Code:     "IC-NE-400",
+		Property: "name",
+		Message:  "name is required",
+	})
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{})
+	//when
+	firstErr := cli.Execute(cli.R().SetBody(map[string]string{}), resty.MethodPost, "/ne/v1/devices")
+	secondErr := cli.Execute(cli.R().SetBody(map[string]string{}), resty.MethodPost, "/ne/v1/devices")
+	//then
+	assert.IsType(t, rest.Error{}, firstErr, "Error should be rest.Error type")
+	restErr := firstErr.(rest.Error)
+	assert.Equal(t, http.StatusBadRequest, restErr.HTTPCode, "HTTP code matches")
+	assert.Equal(t, 1, len(restErr.ApplicationErrors), "Application error should be returned")
+	assert.Equal(t, "IC-NE-400", restErr.ApplicationErrors[0].Code, "Application error code matches")
+	assert.Nil(t, secondErr, "Error should be returned given number of times")
+}
@@ -0,0 +1,254 @@
+package resttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//TestingT is a subset of testing.T used by Spy assertions
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+//Call describes HTTP request captured by Spy
+type Call struct {
+	//Method is HTTP method of a request
+	Method string
+	//Path is escaped URL path of a request
+	Path string
+	//Query holds query parameters of a request
+	Query url.Values
+	//Header holds request headers
+	Header http.Header
+	//Body is request body
+	Body []byte
+}
+
+//RequestMatcher describes expected HTTP request. Only configured attributes are compared
+type RequestMatcher struct {
+	method string
+	path   string
+	query  map[string]string
+	header map[string]string
+	body   interface{}
+	isBody bool
+}
+
+//Spy is http.RoundTripper that captures every request passed through it to underlying
+//transport. Spy can be used with http.Client given to rest.NewClient to assert requests
+//made by code built on rest.Client
+type Spy struct {
+	next  http.RoundTripper
+	mu    sync.Mutex
+	calls []Call
+}
+
+//NewSpy creates new spy on top of a given transport. When given transport is nil,
+//http.DefaultTransport is used
+func NewSpy(next http.RoundTripper) *Spy {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Spy{next: next}
+}
+
+//Expect creates matcher of requests with a given method and path. Empty method matches any method
+func Expect(method string, path string) *RequestMatcher {
+	return &RequestMatcher{
+		method: method,
+		path:   "/" + strings.Trim(path, "/"),
+		query:  make(map[string]string),
+		header: make(map[string]string),
+	}
+}
+
+//WithQuery expects query parameter with a given value
+func (m *RequestMatcher) WithQuery(name string, value string) *RequestMatcher {
+	m.query[name] = value
+	return m
+}
+
+//WithHeader expects header with a given value
+func (m *RequestMatcher) WithHeader(name string, value string) *RequestMatcher {
+	m.header[name] = value
+	return m
+}
+
+//WithJSONBody expects JSON body semantically equal to a given value
+func (m *RequestMatcher) WithJSONBody(v interface{}) *RequestMatcher {
+	m.body = v
+	m.isBody = true
+	return m
+}
+
+//RoundTrip captures request and passes it to underlying transport
+func (s *Spy) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.calls = append(s.calls, Call{
+		Method: req.Method,
+		Path:   req.URL.EscapedPath(),
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	s.mu.Unlock()
+	return s.next.RoundTrip(req)
+}
+
+//Calls returns captured requests in order of execution
+func (s *Spy) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	calls := make([]Call, len(s.calls))
+	copy(calls, s.calls)
+	return calls
+}
+
+//Reset removes captured requests
+func (s *Spy) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+}
+
+//Count returns number of captured requests that match given matcher
+func (s *Spy) Count(m *RequestMatcher) int {
+	count := 0
+	for _, call := range s.Calls() {
+		if len(m.diff(call)) == 0 {
+			count++
+		}
+	}
+	return count
+}
+
+//AssertCalled asserts that at least one captured request matches given matcher. When none matches,
+//differences between expected request and captured requests are reported
+func (s *Spy) AssertCalled(t TestingT, m *RequestMatcher) bool {
+	if s.Count(m) > 0 {
+		return true
+	}
+	t.Errorf("expected request %s was not made\n%s", m, s.describeDiffs(m))
+	return false
+}
+
+//AssertCallCount asserts that given number of captured requests match given matcher
+func (s *Spy) AssertCallCount(t TestingT, m *RequestMatcher, expected int) bool {
+	if count := s.Count(m); count != expected {
+		t.Errorf("expected request %s to be made %d times, but it was made %d times\n%s", m, expected, count, s.describeDiffs(m))
+		return false
+	}
+	return true
+}
+
+//AssertNotCalled asserts that none of captured requests match given matcher
+func (s *Spy) AssertNotCalled(t TestingT, m *RequestMatcher) bool {
+	return s.AssertCallCount(t, m, 0)
+}
+
+func (m *RequestMatcher) String() string {
+	var b strings.Builder
+	method := m.method
+	if method == "" {
+		method = "*"
+	}
+	fmt.Fprintf(&b, "%s %s", method, m.path)
+	for _, name := range sortedKeys(m.query) {
+		fmt.Fprintf(&b, " query[%s]=%q", name, m.query[name])
+	}
+	for _, name := range sortedKeys(m.header) {
+		fmt.Fprintf(&b, " header[%s]=%q", name, m.header[name])
+	}
+	if m.isBody {
+		body, _ := json.Marshal(m.body)
+		fmt.Fprintf(&b, " body=%s", body)
+	}
+	return b.String()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported package methods
+//_______________________________________________________________________
+
+func (m *RequestMatcher) diff(call Call) []string {
+	var diffs []string
+	if m.method != "" && m.method != call.Method {
+		diffs = append(diffs, fmt.Sprintf("method: expected %s, got %s", m.method, call.Method))
+	}
+	if m.path != call.Path {
+		diffs = append(diffs, fmt.Sprintf("path: expected %s, got %s", m.path, call.Path))
+	}
+	for _, name := range sortedKeys(m.query) {
+		if got, ok := call.Query[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("query[%s]: expected %q, got none", name, m.query[name]))
+		} else if len(got) != 1 || got[0] != m.query[name] {
+			diffs = append(diffs, fmt.Sprintf("query[%s]: expected %q, got %q", name, m.query[name], strings.Join(got, ",")))
+		}
+	}
+	for _, name := range sortedKeys(m.header) {
+		if got := call.Header.Get(name); got != m.header[name] {
+			diffs = append(diffs, fmt.Sprintf("header[%s]: expected %q, got %q", name, m.header[name], got))
+		}
+	}
+	if m.isBody {
+		if d, ok := jsonDiff(m.body, call.Body); !ok {
+			diffs = append(diffs, d)
+		}
+	}
+	return diffs
+}
+
+func (s *Spy) describeDiffs(m *RequestMatcher) string {
+	calls := s.Calls()
+	if len(calls) == 0 {
+		return "no requests were made"
+	}
+	var b strings.Builder
+	b.WriteString("captured requests:")
+	for i, call := range calls {
+		fmt.Fprintf(&b, "\n  #%d %s %s", i+1, call.Method, call.Path)
+		if len(call.Query) > 0 {
+			fmt.Fprintf(&b, "?%s", call.Query.Encode())
+		}
+		for _, d := range m.diff(call) {
+			fmt.Fprintf(&b, "\n      %s", d)
+		}
+	}
+	return b.String()
+}
+
+func jsonDiff(expected interface{}, actual []byte) (string, bool) {
+	expectedJSON, err := json.Marshal(expected)
+	if err != nil {
+		return fmt.Sprintf("body: cannot encode expected body: %s", err), false
+	}
+	var expectedValue, actualValue interface{}
+	json.Unmarshal(expectedJSON, &expectedValue)
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		return fmt.Sprintf("body: expected %s, got non-JSON %q", expectedJSON, actual), false
+	}
+	if reflect.DeepEqual(expectedValue, actualValue) {
+		return "", true
+	}
+	actualJSON, _ := json.Marshal(actualValue)
+	return fmt.Sprintf("body: expected %s, got %s", expectedJSON, actualJSON), false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
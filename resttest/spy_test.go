@@ -0,0 +1,64 @@
+package resttest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/equinix/rest-go"
+	"github.com/go-resty/resty/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingT struct {
+	errors []string
+}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func TestSpyAssertions(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	devices := srv.Collection("/ne/v1/devices")
+	for i := 0; i < 3; i++ {
+		devices.Put("/ne/v1/devices", map[string]string{})
+	}
+	spy := NewSpy(nil)
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: spy})
+	cli.SetPageSize(2)
+	//when
+	_, listErr := cli.GetPaginated("/ne/v1/devices", &testDevicesPage{}, rest.DefaultPagingConfig())
+	createErr := cli.Execute(cli.R().SetBody(map[string]string{"name": "router"}), resty.MethodPost, "/ne/v1/devices")
+	//then
+	assert.Nil(t, listErr, "Error should not be returned")
+	assert.Nil(t, createErr, "Error should not be returned")
+	assert.Equal(t, 3, len(spy.Calls()), "All requests should be captured")
+	spy.AssertCalled(t, Expect(resty.MethodGet, "/ne/v1/devices").WithQuery("size", "2").WithQuery("page", "2"))
+	spy.AssertCallCount(t, Expect(resty.MethodGet, "/ne/v1/devices").WithQuery("size", "2"), 2)
+	spy.AssertCalled(t, Expect(resty.MethodPost, "/ne/v1/devices").
+		WithHeader("Content-Type", "application/json").
+		WithJSONBody(map[string]string{"name": "router"}))
+	spy.AssertNotCalled(t, Expect(resty.MethodDelete, "/ne/v1/devices"))
+}
+
+func TestSpyDiff(t *testing.T) {
+	//given
+	srv := NewServer()
+	defer srv.Close()
+	srv.Collection("/ne/v1/devices")
+	spy := NewSpy(nil)
+	cli := rest.NewClient(context.Background(), srv.URL, &http.Client{Transport: spy})
+	cli.Execute(cli.R().SetBody(map[string]string{"name": "router"}), resty.MethodPost, "/ne/v1/devices")
+	recT := &recordingT{}
+	//when
+	ok := spy.AssertCalled(recT, Expect(resty.MethodPost, "/ne/v1/devices").WithJSONBody(map[string]string{"name": "switch"}))
+	//then
+	assert.False(t, ok, "Assertion should fail")
+	assert.Equal(t, 1, len(recT.errors), "Failure should be reported")
+	assert.Regexp(t, regexp.MustCompile(`body: expected \{"name":"switch"\}, got \{"name":"router"\}`), recT.errors[0], "Body difference should be reported")
+}